@@ -0,0 +1,86 @@
+package setto
+
+import (
+	"fmt"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// Signing algorithms accepted for ID tokens.
+const (
+	AlgRS256 = "RS256"
+	AlgRS384 = "RS384"
+	AlgRS512 = "RS512"
+	AlgPS256 = "PS256"
+	AlgPS384 = "PS384"
+	AlgPS512 = "PS512"
+	AlgES256 = "ES256"
+	AlgES384 = "ES384"
+	AlgES512 = "ES512"
+	AlgEdDSA = "EdDSA"
+)
+
+// algorithmKeys maps each supported alg to the key type and, for EC and OKP
+// keys, the curve it must be used with.
+var algorithmKeys = map[string]struct {
+	kty jwa.KeyType
+	crv jwa.EllipticCurveAlgorithm
+}{
+	AlgRS256: {kty: jwa.RSA},
+	AlgRS384: {kty: jwa.RSA},
+	AlgRS512: {kty: jwa.RSA},
+	AlgPS256: {kty: jwa.RSA},
+	AlgPS384: {kty: jwa.RSA},
+	AlgPS512: {kty: jwa.RSA},
+	AlgES256: {kty: jwa.EC, crv: jwa.P256},
+	AlgES384: {kty: jwa.EC, crv: jwa.P384},
+	AlgES512: {kty: jwa.EC, crv: jwa.P521},
+	AlgEdDSA: {kty: jwa.OKP, crv: jwa.Ed25519},
+}
+
+// WithAllowedAlgorithms restricts the signing algorithms accepted in ID
+// tokens, e.g. WithAllowedAlgorithms(setto.AlgRS256, setto.AlgES256).
+// By default any supported algorithm is accepted as long as it matches the
+// signing key's alg, or its key type and curve when the key has no alg.
+// With NewVerifierFromDiscovery the default is the discovered
+// id_token_signing_alg_values_supported.
+func WithAllowedAlgorithms(algs ...string) VerifierOption {
+	return func(o *verifierOptions) { o.allowedAlgorithms = algs }
+}
+
+func isSupportedAlgorithm(alg string) bool {
+	_, ok := algorithmKeys[alg]
+	return ok
+}
+
+// checkKeyAlgorithm reports whether key may verify a token signed with alg.
+// Binding alg to the key's type and curve, and to the key's own alg if it
+// advertises one, blocks algorithm-confusion attacks such as presenting an
+// RSA key's material under a different algorithm.
+func checkKeyAlgorithm(key jwk.Key, alg string) error {
+	want := algorithmKeys[alg]
+	if key.KeyType() != want.kty {
+		return fmt.Errorf("signing method %s does not match %s key %s", alg, key.KeyType(), key.KeyID())
+	}
+	if keyAlg := key.Algorithm().String(); keyAlg != "" && keyAlg != alg {
+		return fmt.Errorf("signing method %s does not match alg %s of key %s", alg, keyAlg, key.KeyID())
+	}
+	if want.crv == "" {
+		return nil
+	}
+
+	var crv jwa.EllipticCurveAlgorithm
+	switch k := key.(type) {
+	case jwk.ECDSAPublicKey:
+		crv = k.Crv()
+	case jwk.OKPPublicKey:
+		crv = k.Crv()
+	default:
+		return fmt.Errorf("key %s is not a public key", key.KeyID())
+	}
+	if crv != want.crv {
+		return fmt.Errorf("signing method %s requires curve %s, key %s uses %s", alg, want.crv, key.KeyID(), crv)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package setto
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// AuthErrorResponder writes the response for a request that failed
+// authentication. err is ErrMissingToken, ErrEmailNotVerified or an error
+// returned by Verifier.VerifyIDToken.
+type AuthErrorResponder func(w http.ResponseWriter, r *http.Request, err error)
+
+// AuthOption configures AuthMiddleware.
+type AuthOption func(*authOptions)
+
+type authOptions struct {
+	requireEmail bool
+	onError      AuthErrorResponder
+	skipPaths    []string
+}
+
+// WithRequireVerifiedEmail rejects tokens whose email is not verified,
+// as Verifier.VerifyIDTokenRequireEmail does.
+func WithRequireVerifiedEmail() AuthOption {
+	return func(o *authOptions) { o.requireEmail = true }
+}
+
+// WithAuthErrorResponder replaces the default error response.
+// The default replies 403 for ErrEmailNotVerified, 503 for
+// ErrVerifierUnavailable and 401 with a WWW-Authenticate header otherwise.
+func WithAuthErrorResponder(fn AuthErrorResponder) AuthOption {
+	return func(o *authOptions) { o.onError = fn }
+}
+
+// WithSkipPaths lets requests for the given paths through without a token.
+// A path ending in "/" matches every path below it, e.g. "/public/".
+func WithSkipPaths(paths ...string) AuthOption {
+	return func(o *authOptions) { o.skipPaths = append(o.skipPaths, paths...) }
+}
+
+type claimsContextKey struct{}
+
+// ContextWithClaims returns a copy of ctx carrying claims.
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims stored by AuthMiddleware, if any.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
+	return claims, ok && claims != nil
+}
+
+// AuthMiddleware returns net/http middleware that authenticates requests with
+// a Setto ID token sent as "Authorization: Bearer <token>". Verified claims are
+// stored in the request context; read them with ClaimsFromContext.
+//
+//	mux.Handle("/api/", setto.AuthMiddleware(verifier,
+//		setto.WithRequireVerifiedEmail(),
+//		setto.WithSkipPaths("/api/health"),
+//	)(apiHandler))
+func AuthMiddleware(verifier *Verifier, opts ...AuthOption) func(http.Handler) http.Handler {
+	options := &authOptions{onError: defaultAuthErrorResponder}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if options.skip(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := bearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				options.onError(w, r, ErrMissingToken)
+				return
+			}
+
+			verify := verifier.VerifyIDToken
+			if options.requireEmail {
+				verify = verifier.VerifyIDTokenRequireEmail
+			}
+			claims, err := verify(r.Context(), token)
+			if err != nil {
+				options.onError(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+func (o *authOptions) skip(path string) bool {
+	for _, p := range o.skipPaths {
+		if path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// bearerToken extracts the token from an Authorization header value.
+// The scheme is matched case-insensitively per RFC 6750.
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func defaultAuthErrorResponder(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrMissingToken):
+		w.Header().Set("WWW-Authenticate", `Bearer`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	case errors.Is(err, ErrEmailNotVerified):
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", error_description="email not verified"`)
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	case errors.Is(err, ErrVerifierUnavailable):
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	default:
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	}
+}
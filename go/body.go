@@ -0,0 +1,120 @@
+package setto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// defaultMaxResponseSize bounds response bodies unless WithMaxResponseSize is used.
+const defaultMaxResponseSize = 10 << 20 // 10 MiB
+
+// WithMaxResponseSize limits the size of response bodies the client will read.
+// Larger responses fail with *ResponseTooLargeError. Default: 10 MiB.
+func WithMaxResponseSize(n int64) Option {
+	return func(o *clientOptions) {
+		if n > 0 {
+			o.maxResponseSize = n
+		}
+	}
+}
+
+// bufferPool holds buffers for reading response bodies.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// maxPooledBuffer keeps unusually large buffers from being retained by the pool.
+const maxPooledBuffer = 64 << 10
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	b.Reset()
+	bufferPool.Put(b)
+}
+
+// limitedReader reads at most limit bytes from r and fails with
+// *ResponseTooLargeError if r holds more.
+type limitedReader struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+}
+
+func newLimitedReader(r io.Reader, limit int64) *limitedReader {
+	return &limitedReader{r: r, limit: limit, remaining: limit}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// Probe for one more byte to tell "exactly limit" from "too large".
+		var probe [1]byte
+		n, err := l.r.Read(probe[:])
+		if n > 0 {
+			return 0, &ResponseTooLargeError{Limit: l.limit}
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// decodeBody reads a JSON response body into a pooled buffer, bounded by the
+// limitedReader around it, and decodes it into result. An empty body leaves
+// result untouched.
+func decodeBody(r io.Reader, result interface{}) error {
+	if result == nil {
+		_, err := io.Copy(io.Discard, r)
+		return readError(err)
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if _, err := buf.ReadFrom(r); err != nil {
+		return readError(err)
+	}
+	if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(buf.Bytes(), result); err != nil {
+		return fmt.Errorf("setto: failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// readErrorBody reads an error response into a pooled buffer and parses it.
+func readErrorBody(r io.Reader, parse func([]byte) error) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if _, err := buf.ReadFrom(r); err != nil {
+		return readError(err)
+	}
+	return parse(buf.Bytes())
+}
+
+// readError classifies an error that occurred while reading a response body.
+func readError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var tooLarge *ResponseTooLargeError
+	if errors.As(err, &tooLarge) {
+		return tooLarge
+	}
+	return &NetworkError{Cause: fmt.Errorf("read response: %w", err)}
+}
@@ -0,0 +1,300 @@
+package setto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through and tracks their failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests immediately with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// EndpointGroup identifies a group of endpoints that share a circuit breaker.
+type EndpointGroup string
+
+const (
+	EndpointGroupIntegration     EndpointGroup = "integration"      // /api/integration/*, /api/merchant/*
+	EndpointGroupExternalPayment EndpointGroup = "external_payment" // /api/external/*
+	EndpointGroupJWKS            EndpointGroup = "jwks"             // JWKS fetches by Verifier
+)
+
+// endpointGroupFor returns the breaker group of an API path.
+func endpointGroupFor(path string) EndpointGroup {
+	switch {
+	case strings.HasPrefix(path, "/api/external/"):
+		return EndpointGroupExternalPayment
+	case strings.HasPrefix(path, "/.well-known/"):
+		return EndpointGroupJWKS
+	}
+	return EndpointGroupIntegration
+}
+
+const (
+	defaultBreakerFailureRatio   = 0.5
+	defaultBreakerMinRequests    = 10
+	defaultBreakerWindow         = 30 * time.Second
+	defaultBreakerOpenTimeout    = 15 * time.Second
+	defaultBreakerHalfOpenProbes = 1
+)
+
+// CircuitBreakerConfig configures the circuit breaker enabled by WithCircuitBreaker.
+//
+// Failures are network errors and 5xx responses. Client errors (4xx),
+// including rate limiting, do not count against the circuit.
+type CircuitBreakerConfig struct {
+	FailureRatio   float64       // Failure ratio that opens the circuit. Default: 0.5.
+	MinRequests    int           // Requests per window before the ratio is evaluated. Default: 10.
+	Window         time.Duration // Interval after which closed-state counts are reset. Default: 30s.
+	OpenTimeout    time.Duration // Time spent open before probing. Default: 15s.
+	HalfOpenProbes int           // Successful probes required to close again. Default: 1.
+
+	// OnStateChange is called after a group's circuit changes state.
+	// It must not block.
+	OnStateChange func(group EndpointGroup, from, to CircuitState)
+}
+
+// WithCircuitBreaker enables a circuit breaker per EndpointGroup. Default: disabled.
+// While a group's circuit is open, its calls fail immediately with ErrCircuitOpen.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) Option {
+	return func(o *clientOptions) { o.breaker = newCircuitBreaker(cfg) }
+}
+
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu       sync.Mutex
+	circuits map[EndpointGroup]*circuit
+}
+
+type circuit struct {
+	state       CircuitState
+	generation  uint64 // incremented on every state change and window reset
+	windowStart time.Time
+	openedAt    time.Time
+	requests    int
+	failures    int
+	probes      int // in-flight probes while half-open
+	successes   int // successful probes while half-open
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.FailureRatio <= 0 || cfg.FailureRatio > 1 {
+		cfg.FailureRatio = defaultBreakerFailureRatio
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = defaultBreakerMinRequests
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultBreakerWindow
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = defaultBreakerOpenTimeout
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = defaultBreakerHalfOpenProbes
+	}
+	return &circuitBreaker{
+		cfg:      cfg,
+		circuits: make(map[EndpointGroup]*circuit),
+	}
+}
+
+// allow reports whether a request to group may proceed. On success it returns
+// the circuit generation that must be passed back to record.
+func (b *circuitBreaker) allow(group EndpointGroup) (uint64, error) {
+	b.mu.Lock()
+	now := time.Now()
+	c := b.circuit(group, now)
+	from := c.state
+
+	switch c.state {
+	case CircuitClosed:
+		if now.Sub(c.windowStart) >= b.cfg.Window {
+			c.resetWindow(now)
+		}
+	case CircuitOpen:
+		if now.Sub(c.openedAt) < b.cfg.OpenTimeout {
+			b.mu.Unlock()
+			return 0, fmt.Errorf("%w: %s", ErrCircuitOpen, group)
+		}
+		c.setState(CircuitHalfOpen, now)
+	}
+
+	if c.state == CircuitHalfOpen {
+		if c.probes+c.successes >= b.cfg.HalfOpenProbes {
+			to := c.state
+			b.mu.Unlock()
+			b.notify(group, from, to)
+			return 0, fmt.Errorf("%w: %s", ErrCircuitOpen, group)
+		}
+		c.probes++
+	}
+	c.requests++
+	gen := c.generation
+	to := c.state
+	b.mu.Unlock()
+
+	b.notify(group, from, to)
+	return gen, nil
+}
+
+// circuitOutcome classifies a finished request for the circuit breaker.
+type circuitOutcome int
+
+const (
+	outcomeSuccess circuitOutcome = iota
+	outcomeFailure
+	// outcomeAborted is a request that ended before the server's health could
+	// be judged, e.g. cancelled by the caller. It counts as neither success
+	// nor failure, and only releases its half-open probe slot.
+	outcomeAborted
+)
+
+// record reports the outcome of a request admitted by allow.
+// Outcomes from a previous generation are ignored.
+func (b *circuitBreaker) record(group EndpointGroup, gen uint64, outcome circuitOutcome) {
+	b.mu.Lock()
+	now := time.Now()
+	c := b.circuit(group, now)
+	from := c.state
+	if gen != c.generation {
+		b.mu.Unlock()
+		return
+	}
+
+	switch c.state {
+	case CircuitClosed:
+		switch outcome {
+		case outcomeAborted:
+			c.requests--
+		case outcomeFailure:
+			c.failures++
+			if c.requests >= b.cfg.MinRequests &&
+				float64(c.failures)/float64(c.requests) >= b.cfg.FailureRatio {
+				c.setState(CircuitOpen, now)
+			}
+		}
+	case CircuitHalfOpen:
+		c.probes--
+		switch outcome {
+		case outcomeFailure:
+			c.setState(CircuitOpen, now)
+		case outcomeSuccess:
+			if c.successes++; c.successes >= b.cfg.HalfOpenProbes {
+				c.setState(CircuitClosed, now)
+			}
+		}
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.notify(group, from, to)
+}
+
+func (b *circuitBreaker) circuit(group EndpointGroup, now time.Time) *circuit {
+	c, ok := b.circuits[group]
+	if !ok {
+		c = &circuit{windowStart: now}
+		b.circuits[group] = c
+	}
+	return c
+}
+
+func (b *circuitBreaker) notify(group EndpointGroup, from, to CircuitState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(group, from, to)
+	}
+}
+
+func (c *circuit) setState(state CircuitState, now time.Time) {
+	c.state = state
+	c.resetWindow(now)
+	c.probes = 0
+	c.successes = 0
+	if state == CircuitOpen {
+		c.openedAt = now
+	}
+}
+
+func (c *circuit) resetWindow(now time.Time) {
+	c.generation++
+	c.windowStart = now
+	c.requests = 0
+	c.failures = 0
+}
+
+// circuitOutcomeOf classifies the error of a finished request. Cancellation
+// and oversized responses are aborted; network errors and 5xx responses are
+// failures; anything else, including 4xx responses, shows a healthy server.
+func circuitOutcomeOf(err error) circuitOutcome {
+	if err == nil {
+		return outcomeSuccess
+	}
+
+	var tooLarge *ResponseTooLargeError
+	if errors.Is(err, context.Canceled) || errors.As(err, &tooLarge) {
+		return outcomeAborted
+	}
+
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return outcomeFailure
+	}
+
+	var walletErr *WalletError
+	if errors.As(err, &walletErr) && (walletErr.HTTPStatus >= 500 || walletErr.Code == SystemRPCFailed) {
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+// breakerTransport guards an http.RoundTripper with a circuit breaker group.
+// It is used for requests the Client does not send itself, such as JWKS fetches.
+type breakerTransport struct {
+	base    http.RoundTripper
+	breaker *circuitBreaker
+	group   EndpointGroup
+}
+
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	gen, err := t.breaker.allow(t.group)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	outcome := outcomeSuccess
+	switch {
+	case errors.Is(err, context.Canceled):
+		outcome = outcomeAborted
+	case err != nil, resp.StatusCode >= 500:
+		outcome = outcomeFailure
+	}
+	t.breaker.record(t.group, gen, outcome)
+	return resp, err
+}
@@ -0,0 +1,68 @@
+package setto
+
+import "sync"
+
+// Well-known EVM chain IDs, as registered under EIP-155
+// (https://github.com/ethereum-lists/chains). They are provided for
+// convenience and are not a list of the chains Setto accepts; the server
+// decides that.
+const (
+	ChainEthereum        int32 = 1
+	ChainOptimism        int32 = 10
+	ChainBSC             int32 = 56
+	ChainBSCTestnet      int32 = 97
+	ChainPolygon         int32 = 137
+	ChainBase            int32 = 8453
+	ChainArbitrum        int32 = 42161
+	ChainPolygonAmoy     int32 = 80002
+	ChainBaseSepolia     int32 = 84532
+	ChainArbitrumSepolia int32 = 421614
+	ChainSepolia         int32 = 11155111
+	ChainOptimismSepolia int32 = 11155420
+)
+
+// ChainType is the address family of a chain.
+type ChainType int
+
+const (
+	ChainTypeUnknown ChainType = iota
+	ChainTypeEVM
+	ChainTypeSVM
+)
+
+var (
+	chainTypesMu sync.RWMutex
+	chainTypes   = map[int32]ChainType{
+		ChainEthereum:        ChainTypeEVM,
+		ChainOptimism:        ChainTypeEVM,
+		ChainBSC:             ChainTypeEVM,
+		ChainBSCTestnet:      ChainTypeEVM,
+		ChainPolygon:         ChainTypeEVM,
+		ChainBase:            ChainTypeEVM,
+		ChainArbitrum:        ChainTypeEVM,
+		ChainPolygonAmoy:     ChainTypeEVM,
+		ChainBaseSepolia:     ChainTypeEVM,
+		ChainArbitrumSepolia: ChainTypeEVM,
+		ChainSepolia:         ChainTypeEVM,
+		ChainOptimismSepolia: ChainTypeEVM,
+	}
+)
+
+// ChainTypeOf returns the address family of chainID, or ChainTypeUnknown
+// if it is neither one of the Chain* constants nor registered with
+// RegisterChain. An unknown chain may still be supported by the server.
+func ChainTypeOf(chainID int32) ChainType {
+	chainTypesMu.RLock()
+	defer chainTypesMu.RUnlock()
+	return chainTypes[chainID]
+}
+
+// RegisterChain records the address family of chainID so that Validate
+// checks contract addresses on it. Setto does not publish numeric IDs for
+// Solana clusters, so none are predefined: register the IDs your account
+// uses with ChainTypeSVM. It is safe to call concurrently.
+func RegisterChain(chainID int32, chainType ChainType) {
+	chainTypesMu.Lock()
+	defer chainTypesMu.Unlock()
+	chainTypes[chainID] = chainType
+}
@@ -0,0 +1,94 @@
+package setto
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// claimRules holds the optional claim checks configured on a Verifier.
+type claimRules struct {
+	audiences       []string
+	authorizedParty string
+	leeway          time.Duration
+	maxAge          time.Duration
+	requiredClaims  []string
+}
+
+// WithAudience requires the token's aud claim to contain at least one of
+// auds, typically your integration's client ID. Tokens minted for other
+// integrations then fail with ErrAudienceMismatch.
+func WithAudience(auds ...string) VerifierOption {
+	return func(o *verifierOptions) { o.rules.audiences = append(o.rules.audiences, auds...) }
+}
+
+// WithAuthorizedParty requires the token's azp claim to equal azp.
+// A mismatch or missing azp fails with ErrAZPMismatch.
+func WithAuthorizedParty(azp string) VerifierOption {
+	return func(o *verifierOptions) { o.rules.authorizedParty = azp }
+}
+
+// WithLeeway allows for clock skew between Setto and this server when
+// checking exp, nbf, iat and the maximum age.
+func WithLeeway(d time.Duration) VerifierOption {
+	return func(o *verifierOptions) { o.rules.leeway = d }
+}
+
+// WithMaxAge rejects tokens issued more than d ago, based on iat, with
+// ErrTokenTooOld. Tokens without iat fail with ErrMissingClaim.
+func WithMaxAge(d time.Duration) VerifierOption {
+	return func(o *verifierOptions) { o.rules.maxAge = d }
+}
+
+// WithRequiredClaims rejects tokens lacking any of the named claims,
+// e.g. "email" or "iat", with ErrMissingClaim.
+func WithRequiredClaims(names ...string) VerifierOption {
+	return func(o *verifierOptions) { o.rules.requiredClaims = append(o.rules.requiredClaims, names...) }
+}
+
+// check applies the configured rules to a token's claims. exp and nbf are
+// validated by the JWT parser with the same leeway.
+func (r *claimRules) check(claims jwt.MapClaims, now time.Time) error {
+	for _, name := range r.requiredClaims {
+		if claims[name] == nil {
+			return fmt.Errorf("%w: %s", ErrMissingClaim, name)
+		}
+	}
+
+	if len(r.audiences) > 0 {
+		aud, err := claims.GetAudience()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrTokenInvalid, err)
+		}
+		if !slices.ContainsFunc(aud, func(a string) bool { return slices.Contains(r.audiences, a) }) {
+			return fmt.Errorf("%w: got %v", ErrAudienceMismatch, []string(aud))
+		}
+	}
+
+	if r.authorizedParty != "" {
+		azp, _ := claims["azp"].(string)
+		if azp != r.authorizedParty {
+			return fmt.Errorf("%w: expected %s, got %q", ErrAZPMismatch, r.authorizedParty, azp)
+		}
+	}
+
+	if r.maxAge > 0 {
+		iat, err := claims.GetIssuedAt()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrTokenInvalid, err)
+		}
+		if iat == nil {
+			return fmt.Errorf("%w: iat", ErrMissingClaim)
+		}
+		if iat.After(now.Add(r.leeway)) {
+			return fmt.Errorf("%w: issued in the future", ErrTokenNotYetValid)
+		}
+		if age := now.Sub(iat.Time); age > r.maxAge+r.leeway {
+			return fmt.Errorf("%w: issued %s ago, limit %s", ErrTokenTooOld, age.Round(time.Second), r.maxAge)
+		}
+	}
+
+	return nil
+}
@@ -18,9 +18,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Environment represents the Setto environment.
@@ -44,6 +49,10 @@ const (
 type Config struct {
 	APIKey      string      // Integration API Key (sk_setto.xxx)
 	Environment Environment // Production or Development
+
+	// Credentials supplies API keys at request time, enabling key rotation.
+	// If set, APIKey is ignored.
+	Credentials CredentialProvider
 }
 
 // Option configures the Client.
@@ -53,6 +62,20 @@ type clientOptions struct {
 	timeout    time.Duration
 	httpClient *http.Client
 	baseURL    string
+	retry      RetryPolicy
+
+	limiter        *rateLimiter
+	maxConcurrency int
+	breaker        *circuitBreaker
+	middleware     []Middleware
+
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagator     propagation.TextMapPropagator
+
+	logger *slog.Logger
+
+	maxResponseSize int64
 }
 
 // WithTimeout sets the HTTP client timeout. Default: 30s.
@@ -70,23 +93,63 @@ func WithBaseURL(url string) Option {
 	return func(o *clientOptions) { o.baseURL = url }
 }
 
+// CallOption configures a single API call.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	idempotencyKey string
+	idempotencyRef string
+	ott            *OTT
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
 // Client is the Setto Wallet SDK client.
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
+	credentials CredentialProvider
+	baseURL     string
+	httpClient  *http.Client
+	retry       RetryPolicy
+	limiter     *rateLimiter
+	sem         chan struct{} // nil if concurrency is unlimited
+	breaker     *circuitBreaker
+	handler     Handler // roundTrip wrapped by the configured middleware
+
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+
+	logger *slog.Logger
+
+	maxResponseSize int64
 }
 
 // NewClient creates a new Setto SDK client.
 func NewClient(cfg Config, opts ...Option) (*Client, error) {
-	if cfg.APIKey == "" {
-		return nil, fmt.Errorf("setto: API key is required")
+	credentials := cfg.Credentials
+	if credentials == nil {
+		if err := validateAPIKey(cfg.APIKey); err != nil {
+			return nil, err
+		}
+		credentials = StaticCredentials(cfg.APIKey)
 	}
-	if !strings.HasPrefix(cfg.APIKey, "sk_setto.") {
-		return nil, fmt.Errorf("setto: API key must start with 'sk_setto.'")
+	key, err := credentials.APIKey(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAPIKey(key); err != nil {
+		return nil, err
 	}
 
-	options := &clientOptions{timeout: defaultTimeout}
+	options := &clientOptions{
+		timeout:         defaultTimeout,
+		maxResponseSize: defaultMaxResponseSize,
+	}
 	for _, opt := range opts {
 		opt(options)
 	}
@@ -110,69 +173,246 @@ func NewClient(cfg Config, opts ...Option) (*Client, error) {
 		httpClient = &http.Client{Timeout: options.timeout}
 	}
 
-	return &Client{
-		apiKey:     cfg.APIKey,
-		baseURL:    strings.TrimRight(baseURL, "/"),
-		httpClient: httpClient,
-	}, nil
+	var sem chan struct{}
+	if options.maxConcurrency > 0 {
+		sem = make(chan struct{}, options.maxConcurrency)
+	}
+
+	c := &Client{
+		credentials: credentials,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		httpClient:  httpClient,
+		retry:       options.retry,
+		limiter:     options.limiter,
+		sem:         sem,
+		breaker:     options.breaker,
+
+		tracerProvider: options.tracerProvider,
+		meterProvider:  options.meterProvider,
+
+		logger: options.logger,
+
+		maxResponseSize: options.maxResponseSize,
+	}
+	if c.logger == nil {
+		c.logger = slog.New(slog.DiscardHandler)
+	}
+
+	middleware := options.middleware
+	if t := newTelemetry(options.tracerProvider, options.meterProvider, options.propagator); t != nil {
+		middleware = append([]Middleware{t.middleware()}, middleware...)
+	}
+	c.handler = chain(c.roundTrip, middleware)
+
+	return c, nil
 }
 
 // NewVerifier creates a JWT Verifier configured from this client's base URL.
-func (c *Client) NewVerifier() *Verifier {
+// If the client has a circuit breaker, JWKS fetches use its JWKS group.
+// The verifier shares the client's tracer and meter providers and logger.
+func (c *Client) NewVerifier(opts ...VerifierOption) *Verifier {
+	opts = append([]VerifierOption{
+		WithVerifierTracerProvider(c.tracerProvider),
+		WithVerifierMeterProvider(c.meterProvider),
+		WithVerifierLogger(c.logger),
+	}, opts...)
+	if c.breaker != nil {
+		base := c.httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		httpClient := *c.httpClient
+		httpClient.Transport = &breakerTransport{base: base, breaker: c.breaker, group: EndpointGroupJWKS}
+		opts = append([]VerifierOption{WithJWKSHTTPClient(&httpClient)}, opts...)
+	}
 	return NewVerifier(
 		c.baseURL+"/.well-known/jwks.json",
 		c.baseURL,
+		opts...,
 	)
 }
 
-// do executes an HTTP request with authentication and error handling.
-func (c *Client) do(ctx context.Context, method, path string, body interface{}, result interface{}) error {
-	var bodyReader io.Reader
-	if body != nil {
-		data, err := json.Marshal(body)
+// do executes a logical SDK operation through the middleware chain.
+// op is the operation name reported to middleware, e.g. "InitiatePayment".
+func (c *Client) do(ctx context.Context, op, method, path string, body interface{}, result interface{}, opts ...CallOption) error {
+	callOpts := newCallOptions(opts)
+
+	req := &Request{
+		Operation: op,
+		Method:    method,
+		Path:      path,
+		Header:    make(http.Header),
+		Body:      body,
+		Result:    result,
+	}
+	if key := callOpts.idempotencyKeyFor(method, path); key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+
+	_, err := c.handler(ctx, req)
+	return err
+}
+
+// roundTrip is the innermost Handler. It sends req with authentication and
+// error handling, retrying failed attempts according to the client's RetryPolicy.
+func (c *Client) roundTrip(ctx context.Context, r *Request) (*Response, error) {
+	var data []byte
+	if r.Body != nil {
+		var err error
+		data, err = json.Marshal(r.Body)
 		if err != nil {
-			return fmt.Errorf("setto: failed to marshal request: %w", err)
+			return nil, fmt.Errorf("setto: failed to marshal request: %w", err)
 		}
-		bodyReader = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, r.Method, c.baseURL+r.Path, nil)
 	if err != nil {
-		return &NetworkError{Cause: err}
+		return nil, &NetworkError{Cause: err}
 	}
 
-	req.Header.Set("X-API-Key", c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "setto-server-sdk-go/"+sdkVersion)
+	for name, values := range r.Header {
+		req.Header[name] = values
+	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return &NetworkError{Cause: err}
+	failovers := 0
+	for attempt := 1; ; attempt++ {
+		key, err := c.apiKey(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-API-Key", key)
+
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "setto: request",
+			slog.String("operation", r.Operation),
+			slog.String("method", r.Method),
+			slog.String("path", r.Path),
+			slog.Int("attempt", attempt),
+			slog.Any("body", logBody{r.Body}),
+		)
+
+		start := time.Now()
+		resp, err := c.send(req, data, r.Result)
+		if resp != nil {
+			resp.Attempts = attempt
+		}
+		if err == nil {
+			c.logger.LogAttrs(ctx, slog.LevelDebug, "setto: response",
+				slog.String("operation", r.Operation),
+				slog.Int("status", resp.StatusCode),
+				slog.Duration("duration", time.Since(start)),
+				slog.Any("result", logBody{r.Result}),
+			)
+			return resp, nil
+		}
+
+		if isUnauthorized(err) && failovers < maxKeyFailovers {
+			c.credentials.Reject(key)
+			if next, nextErr := c.apiKey(ctx); nextErr == nil && next != key {
+				failovers++
+				c.logger.LogAttrs(ctx, slog.LevelWarn, "setto: API key rejected, failing over",
+					slog.String("operation", r.Operation),
+					slog.String("rejected_key", redactAPIKey(key)),
+				)
+				continue
+			}
+		}
+
+		delay, ok := c.retry.backoff(attempt, err)
+		if attempt >= c.retry.MaxAttempts || !isIdempotent(req) || !isRetryable(err) || !ok {
+			c.logger.LogAttrs(ctx, slog.LevelWarn, "setto: request failed",
+				slog.String("operation", r.Operation),
+				slog.Int("attempt", attempt),
+				slog.Duration("duration", time.Since(start)),
+				slog.Any("error", err),
+			)
+			return resp, err
+		}
+
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "setto: retrying request",
+			slog.String("operation", r.Operation),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.Any("error", err),
+		)
+		if !sleepContext(ctx, delay) {
+			return resp, err
+		}
 	}
-	defer resp.Body.Close()
+}
 
-	respBody, err := io.ReadAll(resp.Body)
+// send performs a single attempt of req with the given JSON body.
+// req is cloned so the caller can reuse it for subsequent attempts.
+// The returned Response is non-nil whenever the server answered.
+func (c *Client) send(req *http.Request, data []byte, result interface{}) (_ *Response, err error) {
+	release, err := c.acquire(req.Context())
 	if err != nil {
-		return &NetworkError{Cause: fmt.Errorf("read response: %w", err)}
+		return nil, &NetworkError{Cause: err}
 	}
+	defer release()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return parseHTTPError(resp.StatusCode, respBody)
+	if c.breaker != nil {
+		group := endpointGroupFor(req.URL.Path)
+		gen, allowErr := c.breaker.allow(group)
+		if allowErr != nil {
+			return nil, allowErr
+		}
+		defer func() { c.breaker.record(group, gen, circuitOutcomeOf(err)) }()
 	}
 
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("setto: failed to parse response: %w", err)
+	req = req.Clone(req.Context())
+	if data != nil {
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
 		}
+		req.ContentLength = int64(len(data))
+	}
+
+	httpResp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
 	}
+	defer httpResp.Body.Close()
 
-	return nil
+	if c.limiter != nil {
+		c.limiter.observe(httpResp.Header, time.Now())
+	}
+
+	resp := &Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header}
+
+	if httpResp.ContentLength > c.maxResponseSize {
+		return resp, &ResponseTooLargeError{Limit: c.maxResponseSize}
+	}
+	body := newLimitedReader(httpResp.Body, c.maxResponseSize)
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return resp, readErrorBody(body, func(data []byte) error {
+			err := parseHTTPError(httpResp.StatusCode, httpResp.Header, data)
+			if ottErr := ottErrorFrom(err); ottErr != nil {
+				return ottErr
+			}
+			if we := err.(*WalletError); isIdempotencyConflict(req, we) {
+				return &IdempotencyConflictError{
+					Key:   req.Header.Get(idempotencyKeyHeader),
+					Cause: we,
+				}
+			}
+			return err
+		})
+	}
+
+	return resp, decodeBody(body, result)
 }
 
 // parseHTTPError parses a non-2xx HTTP response into a WalletError.
-func parseHTTPError(statusCode int, body []byte) error {
-	we := &WalletError{HTTPStatus: statusCode}
+func parseHTTPError(statusCode int, header http.Header, body []byte) error {
+	we := &WalletError{
+		HTTPStatus: statusCode,
+		RetryAfter: parseRetryAfter(header, time.Now()),
+	}
 	if len(body) > 0 {
 		_ = json.Unmarshal(body, we)
 	}
@@ -0,0 +1,88 @@
+package setto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const benchPaymentJSON = `{"paymentId":"pay_01HZX3K9Q2","status":"submitted","txHash":"0x8f3c2a91d4e5b6c7a8f9e0d1c2b3a4f5e6d7c8b9a0f1e2d3c4b5a6f7e8d9c0b1","amount":"125.50","currency":"USDC","createdAt":1760000000000}`
+
+const benchVerificationJSON = `{"is_phone_verified":true,"verified_at":1760000000000}`
+
+// newBenchClient returns a Client talking to a local server that answers
+// every request with body.
+func newBenchClient(b *testing.B, body string) *Client {
+	b.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+	b.Cleanup(srv.Close)
+
+	c, err := NewClient(Config{APIKey: "sk_setto.bench", Environment: Development}, WithBaseURL(srv.URL))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return c
+}
+
+func BenchmarkGetPaymentStatus(b *testing.B) {
+	c := newBenchClient(b, benchPaymentJSON)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := c.GetPaymentStatus(ctx, "pay_01HZX3K9Q2"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetVerificationStatus(b *testing.B) {
+	c := newBenchClient(b, benchVerificationJSON)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := c.GetVerificationStatus(ctx, "user_123"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecodeBody measures the pooled decoding used by Client.do.
+func BenchmarkDecodeBody(b *testing.B) {
+	data := []byte(benchPaymentJSON)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var info PaymentInfo
+		r := newLimitedReader(bytes.NewReader(data), defaultMaxResponseSize)
+		if err := decodeBody(r, &info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadAllUnmarshal measures the io.ReadAll and json.Unmarshal
+// decoding that Client.do used before pooling, for comparison.
+func BenchmarkReadAllUnmarshal(b *testing.B) {
+	data := []byte(benchPaymentJSON)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var info PaymentInfo
+		body, err := io.ReadAll(bytes.NewReader(data))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := json.Unmarshal(body, &info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package setto
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const apiKeyPrefix = "sk_setto."
+
+// maxKeyFailovers bounds how many rejected keys a single call fails over from.
+const maxKeyFailovers = 4
+
+// rejectionTTL is how long a rejected key is skipped before it is tried again,
+// so a transient 401 from a proxy or a brief revocation heals on its own.
+const rejectionTTL = 10 * time.Minute
+
+// CredentialProvider supplies the API key sent in the X-API-Key header.
+// It is consulted before every request, so keys can be rotated without
+// restarting. Implementations must be safe for concurrent use.
+type CredentialProvider interface {
+	// APIKey returns the key to use for the next request.
+	APIKey(ctx context.Context) (string, error)
+
+	// Reject reports that the server rejected key with HTTP 401.
+	// Providers holding several keys should prefer the others for a while,
+	// but must keep returning the last remaining key rather than none.
+	Reject(key string)
+}
+
+// StaticCredentials returns a provider for a fixed list of keys, in order of
+// preference. When the server rejects a key, the next one is used; the
+// rejected key is tried again after a while. The last usable key is never
+// given up, so a single key keeps being sent after a 401.
+func StaticCredentials(keys ...string) CredentialProvider {
+	return &staticCredentials{keys: keys}
+}
+
+// EnvCredentials returns a provider that reads the key from the environment
+// variable name on every request. The variable may hold several
+// comma-separated keys, in which case rejected keys are skipped for a while;
+// changing the variable clears all rejections.
+func EnvCredentials(name string) CredentialProvider {
+	return &envCredentials{name: name}
+}
+
+// FileCredentials returns a provider that reads keys from a file, one per
+// line, ignoring blank lines and lines starting with "#". The file is checked
+// for changes at most once per interval (default: 10s), so a rotated key is
+// picked up without a restart. If the file becomes unreadable or empty, the
+// last good keys are kept. Rejected keys are skipped for a while, or until
+// the file changes.
+func FileCredentials(path string, interval time.Duration) CredentialProvider {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &fileCredentials{path: path, interval: interval}
+}
+
+// keyRing remembers keys recently rejected by the server. Rejections expire
+// after rejectionTTL and are cleared whenever the key set changes.
+type keyRing struct {
+	mu       sync.Mutex
+	rejected map[string]time.Time // key -> time of rejection
+	source   string               // key set the rejections apply to
+}
+
+// pick returns the first key that has not been recently rejected.
+func (r *keyRing) pick(keys []string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resetIfChanged(keys)
+
+	now := time.Now()
+	for _, key := range keys {
+		if !r.isRejected(key, now) {
+			return key, nil
+		}
+	}
+	return "", ErrNoValidAPIKey
+}
+
+// reject marks key as rejected unless it is the last usable key of keys.
+func (r *keyRing) reject(key string, keys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resetIfChanged(keys)
+
+	now := time.Now()
+	others := 0
+	for _, k := range keys {
+		if k != key && !r.isRejected(k, now) {
+			others++
+		}
+	}
+	if others == 0 {
+		return
+	}
+	if r.rejected == nil {
+		r.rejected = make(map[string]time.Time)
+	}
+	r.rejected[key] = now
+}
+
+func (r *keyRing) isRejected(key string, now time.Time) bool {
+	at, ok := r.rejected[key]
+	return ok && now.Sub(at) < rejectionTTL
+}
+
+func (r *keyRing) resetIfChanged(keys []string) {
+	source := strings.Join(keys, "\n")
+	if source != r.source {
+		r.source = source
+		r.rejected = nil
+	}
+}
+
+type staticCredentials struct {
+	keyRing
+	keys []string
+}
+
+func (p *staticCredentials) APIKey(context.Context) (string, error) {
+	return p.pick(p.keys)
+}
+
+func (p *staticCredentials) Reject(key string) {
+	p.reject(key, p.keys)
+}
+
+func (p *staticCredentials) describe() string {
+	key, err := p.pick(p.keys)
+	if err != nil {
+		return redacted
+	}
+	return redactAPIKey(key)
+}
+
+type envCredentials struct {
+	keyRing
+	name string
+}
+
+func (p *envCredentials) APIKey(context.Context) (string, error) {
+	keys := p.keys()
+	if len(keys) == 0 {
+		return "", fmt.Errorf("setto: environment variable %s is empty", p.name)
+	}
+	return p.pick(keys)
+}
+
+func (p *envCredentials) Reject(key string) {
+	p.reject(key, p.keys())
+}
+
+func (p *envCredentials) keys() []string {
+	var keys []string
+	for _, key := range strings.Split(os.Getenv(p.name), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (p *envCredentials) describe() string {
+	return "env:" + p.name
+}
+
+type fileCredentials struct {
+	keyRing
+	path     string
+	interval time.Duration
+
+	mu        sync.Mutex
+	keys      []string
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func (p *fileCredentials) APIKey(context.Context) (string, error) {
+	keys, err := p.load()
+	if err != nil {
+		return "", err
+	}
+	return p.pick(keys)
+}
+
+func (p *fileCredentials) Reject(key string) {
+	if keys, err := p.load(); err == nil {
+		p.reject(key, keys)
+	}
+}
+
+// load returns the keys in the file, re-reading it if it changed.
+func (p *fileCredentials) load() ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.keys != nil && now.Sub(p.checkedAt) < p.interval {
+		return p.keys, nil
+	}
+	p.checkedAt = now
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		if p.keys != nil {
+			return p.keys, nil // keep serving the last good keys
+		}
+		return nil, fmt.Errorf("setto: read credentials file: %w", err)
+	}
+	if p.keys != nil && info.ModTime().Equal(p.modTime) {
+		return p.keys, nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		if p.keys != nil {
+			return p.keys, nil
+		}
+		return nil, fmt.Errorf("setto: read credentials file: %w", err)
+	}
+
+	keys := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			keys = append(keys, line)
+		}
+	}
+	if len(keys) == 0 {
+		// An empty file is usually a rotation caught mid-write; keep serving
+		// the last good keys and read it again on the next check.
+		if p.keys != nil {
+			return p.keys, nil
+		}
+		return nil, fmt.Errorf("setto: credentials file %s contains no keys", p.path)
+	}
+
+	p.keys = keys
+	p.modTime = info.ModTime()
+	return p.keys, nil
+}
+
+func (p *fileCredentials) describe() string {
+	return "file:" + p.path
+}
+
+// validateAPIKey checks the format of an Integration API Key.
+func validateAPIKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("setto: API key is required")
+	}
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return fmt.Errorf("setto: API key must start with 'sk_setto.'")
+	}
+	return nil
+}
+
+// apiKey returns the current API key from the client's credential provider.
+func (c *Client) apiKey(ctx context.Context) (string, error) {
+	key, err := c.credentials.APIKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := validateAPIKey(key); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// describeCredentials returns a loggable description of p without secrets.
+func describeCredentials(p CredentialProvider) string {
+	if d, ok := p.(interface{ describe() string }); ok {
+		return d.describe()
+	}
+	return redacted
+}
@@ -0,0 +1,180 @@
+package setto
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+const discoveryPath = "/.well-known/openid-configuration"
+
+// DefaultDiscoveryInterval is how often NewVerifierFromDiscovery re-reads
+// the discovery document by default.
+const DefaultDiscoveryInterval = time.Hour
+
+// maxDiscoverySize bounds the discovery document read into memory.
+const maxDiscoverySize = 1 << 20 // 1 MiB
+
+// discoveryTimeout bounds a discovery request made with the default client,
+// and every background refresh.
+const discoveryTimeout = 10 * time.Second
+
+// discoveryRetryDelay is the first wait after a failed re-read. It doubles
+// with each consecutive failure, up to the discovery interval.
+const discoveryRetryDelay = 30 * time.Second
+
+// WithDiscoveryInterval sets how often a Verifier created by
+// NewVerifierFromDiscovery re-reads the discovery document.
+// Defaults to DefaultDiscoveryInterval.
+func WithDiscoveryInterval(d time.Duration) VerifierOption {
+	return func(o *verifierOptions) { o.discoveryInterval = d }
+}
+
+type discoveryDocument struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// NewVerifierFromDiscovery creates a Verifier configured from the OpenID
+// Connect discovery document at issuerURL + "/.well-known/openid-configuration".
+// As the discovery spec requires, the document's issuer must equal issuerURL
+// exactly; tokens are then checked against that issuer. The JWKS URL and
+// accepted signing algorithms are taken from the document, which is read now
+// and re-read in the background once the discovery interval has passed. If a
+// re-read fails, the previous configuration stays in effect and the next
+// attempt is delayed with exponential backoff. The issuer and jwks_uri must
+// be https URLs.
+func NewVerifierFromDiscovery(ctx context.Context, issuerURL string, opts ...VerifierOption) (*Verifier, error) {
+	options := &verifierOptions{discoveryInterval: DefaultDiscoveryInterval}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	v := NewVerifier("", issuerURL, opts...)
+	v.discoveryURL = strings.TrimRight(issuerURL, "/") + discoveryPath
+	v.discoveryClient = options.httpClient
+	if v.discoveryClient == nil {
+		v.discoveryClient = &http.Client{Timeout: discoveryTimeout}
+	}
+	v.discoveryInterval = options.discoveryInterval
+	if v.discoveryInterval <= 0 {
+		v.discoveryInterval = DefaultDiscoveryInterval
+	}
+
+	if err := v.refreshDiscovery(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// maybeRefreshDiscovery starts a background re-read of the discovery
+// document if it is due. Only one refresh runs at a time, and callers keep
+// using the current configuration meanwhile. The refresh has its own timeout
+// and is not cancelled with ctx, so an abandoned request does not count as a
+// failed refresh.
+func (v *Verifier) maybeRefreshDiscovery(ctx context.Context) {
+	if v.discoveryURL == "" {
+		return
+	}
+	v.mu.RLock()
+	due := !time.Now().Before(v.nextDiscovery)
+	v.mu.RUnlock()
+	if !due || !v.discoveryMu.TryLock() {
+		return
+	}
+
+	go func() {
+		defer v.discoveryMu.Unlock()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), discoveryTimeout)
+		defer cancel()
+		v.refreshDiscoveryWithBackoff(ctx)
+	}()
+}
+
+// refreshDiscoveryWithBackoff re-reads the discovery document, delaying the
+// next attempt with exponential backoff if it fails.
+func (v *Verifier) refreshDiscoveryWithBackoff(ctx context.Context) {
+	if err := v.refreshDiscovery(ctx); err != nil {
+		v.mu.Lock()
+		v.discoveryFailures++
+		delay := discoveryRetryDelay << min(v.discoveryFailures-1, 16)
+		if delay > v.discoveryInterval {
+			delay = v.discoveryInterval
+		}
+		v.nextDiscovery = time.Now().Add(delay)
+		v.mu.Unlock()
+
+		v.logger.LogAttrs(ctx, slog.LevelWarn, "setto: discovery refresh failed, keeping previous configuration",
+			slog.String("url", v.discoveryURL),
+			slog.Duration("retry_in", delay),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// refreshDiscovery fetches the discovery document and applies it. A changed
+// jwks_uri is re-registered with the JWKS cache.
+func (v *Verifier) refreshDiscovery(ctx context.Context) error {
+	doc, err := v.fetchDiscovery(ctx)
+	if err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if doc.Issuer != v.issuer {
+		return fmt.Errorf("%w: discovery document %s names issuer %s, expected %s", ErrIssuerMismatch, v.discoveryURL, doc.Issuer, v.issuer)
+	}
+
+	if doc.JWKSURI != v.jwksURL && v.cache != nil {
+		v.cache.Unregister(v.jwksURL)
+		if err := v.cache.Register(doc.JWKSURI, v.registerOptions()...); err != nil {
+			return fmt.Errorf("setto: failed to register JWKS %s: %w", doc.JWKSURI, err)
+		}
+		v.cachedSet = jwk.NewCachedSet(v.cache, doc.JWKSURI)
+	}
+	v.jwksURL = doc.JWKSURI
+	v.algorithms = doc.IDTokenSigningAlgValuesSupported
+	v.nextDiscovery = time.Now().Add(v.discoveryInterval)
+	v.discoveryFailures = 0
+	return nil
+}
+
+func (v *Verifier) fetchDiscovery(ctx context.Context) (*discoveryDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.discoveryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("setto: invalid discovery URL: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.discoveryClient.Do(req)
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("setto: discovery document %s: HTTP %d", v.discoveryURL, resp.StatusCode)
+	}
+
+	var doc discoveryDocument
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDiscoverySize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("setto: failed to parse discovery document: %w", err)
+	}
+	if doc.Issuer == "" || doc.JWKSURI == "" {
+		return nil, fmt.Errorf("setto: discovery document %s is missing issuer or jwks_uri", v.discoveryURL)
+	}
+	if !strings.HasPrefix(doc.Issuer, "https://") || !strings.HasPrefix(doc.JWKSURI, "https://") {
+		return nil, fmt.Errorf("setto: discovery document %s: issuer and jwks_uri must be https URLs", v.discoveryURL)
+	}
+	return &doc, nil
+}
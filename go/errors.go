@@ -1,8 +1,10 @@
 package setto
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 // System error codes.
@@ -63,6 +65,10 @@ type WalletError struct {
 	Code            string `json:"-"`
 	Message         string `json:"-"`
 	HTTPStatus      int    `json:"-"`
+
+	// RetryAfter is the wait time requested by the server through
+	// Retry-After or rate-limit headers. Zero if none was sent.
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (e *WalletError) Error() string {
@@ -97,11 +103,123 @@ func (e *NetworkError) Unwrap() error {
 	return e.Cause
 }
 
+// IdempotencyConflictError is returned when the server rejects a request
+// with 409 Conflict and no error code because its Idempotency-Key was
+// already used for a different request. A 409 that carries an error code is
+// returned as a plain *WalletError. This usually means the key was derived from a reused
+// order reference.
+type IdempotencyConflictError struct {
+	Key   string
+	Cause *WalletError
+}
+
+func (e *IdempotencyConflictError) Error() string {
+	return fmt.Sprintf("setto: idempotency key conflict (%s): %v", e.Key, e.Cause)
+}
+
+func (e *IdempotencyConflictError) Unwrap() error {
+	return e.Cause
+}
+
+// OTTError is returned when a One-Time Token is missing or rejected, either
+// locally before sending or by the server. Use errors.Is with the ErrOTT*
+// sentinels to tell the cases apart, or NeedsNewToken to decide whether to
+// ask the user for a fresh token.
+type OTTError struct {
+	Code  string // One of the PaymentOTT* codes
+	Cause *WalletError
+}
+
+func (e *OTTError) Error() string {
+	return fmt.Sprintf("setto: one-time token rejected (%s): %v", e.Code, e.Cause)
+}
+
+func (e *OTTError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is the sentinel for e.Code.
+func (e *OTTError) Is(target error) bool {
+	return ottSentinels[e.Code] == target
+}
+
+// NeedsNewToken reports whether the token itself was fine but can no longer
+// be used, so the user should be asked to authorize again. The other codes
+// (required, invalid, scope mismatch) point at the integration, not the user.
+func (e *OTTError) NeedsNewToken() bool {
+	return e.Code == PaymentOTTExpired || e.Code == PaymentOTTAlreadyUsed
+}
+
+// ResponseTooLargeError is returned when a response body exceeds the limit
+// set by WithMaxResponseSize.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("setto: response body exceeds %d bytes", e.Limit)
+}
+
+// PaymentTimeoutError is returned by WaitForPayment when the payment does not
+// reach a terminal state before the deadline.
+type PaymentTimeoutError struct {
+	PaymentID string
+	Last      *PaymentInfo // Last observed status, nil if none was received
+}
+
+func (e *PaymentTimeoutError) Error() string {
+	if e.Last == nil {
+		return fmt.Sprintf("setto: timed out waiting for payment %s", e.PaymentID)
+	}
+	return fmt.Sprintf("setto: timed out waiting for payment %s (last status: %s)", e.PaymentID, e.Last.Status)
+}
+
+// Unwrap allows errors.Is(err, context.DeadlineExceeded).
+func (e *PaymentTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// ErrNoValidAPIKey is returned by a CredentialProvider that has no key left
+// to offer. The built-in providers never reject their last usable key.
+var ErrNoValidAPIKey = errors.New("setto: no valid API key available")
+
+// One-Time Token errors, matched by OTTError via errors.Is.
+var (
+	ErrOTTRequired      = errors.New("setto: one-time token required")
+	ErrOTTInvalid       = errors.New("setto: one-time token is invalid")
+	ErrOTTExpired       = errors.New("setto: one-time token has expired")
+	ErrOTTAlreadyUsed   = errors.New("setto: one-time token already used")
+	ErrOTTScopeMismatch = errors.New("setto: one-time token scope mismatch")
+)
+
+var ottSentinels = map[string]error{
+	PaymentOTTRequired:      ErrOTTRequired,
+	PaymentOTTInvalid:       ErrOTTInvalid,
+	PaymentOTTExpired:       ErrOTTExpired,
+	PaymentOTTAlreadyUsed:   ErrOTTAlreadyUsed,
+	PaymentOTTScopeMismatch: ErrOTTScopeMismatch,
+}
+
+// ErrCircuitOpen is returned without contacting the server while the
+// circuit breaker for the request's endpoint group is open.
+var ErrCircuitOpen = errors.New("setto: circuit breaker is open")
+
 // JWT verification errors.
 var (
 	ErrTokenInvalid     = errors.New("setto: token is invalid")
 	ErrTokenExpired     = errors.New("setto: token has expired")
 	ErrIssuerMismatch   = errors.New("setto: issuer mismatch")
+	ErrAudienceMismatch = errors.New("setto: audience mismatch")
+	ErrAZPMismatch      = errors.New("setto: authorized party mismatch")
+	ErrTokenNotYetValid = errors.New("setto: token is not valid yet")
+	ErrTokenTooOld      = errors.New("setto: token exceeds maximum age")
+	ErrMissingClaim     = errors.New("setto: required claim missing")
 	ErrKeyNotFound      = errors.New("setto: signing key not found")
 	ErrEmailNotVerified = errors.New("setto: email not verified")
+	ErrMissingToken     = errors.New("setto: missing bearer token")
 )
+
+// ErrVerifierUnavailable is returned when a token cannot be checked because
+// the signing keys could not be fetched, e.g. the JWKS endpoint is down or
+// its circuit breaker is open. It says nothing about the token itself.
+var ErrVerifierUnavailable = errors.New("setto: token verifier unavailable")
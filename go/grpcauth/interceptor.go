@@ -0,0 +1,168 @@
+// Package grpcauth authenticates gRPC calls with Setto ID tokens.
+//
+// The interceptors read "authorization: Bearer <id_token>" from the incoming
+// metadata, verify it with a setto.Verifier and store the claims in the
+// context, where setto.ClaimsFromContext finds them:
+//
+//	verifier := client.NewVerifier()
+//	srv := grpc.NewServer(
+//	    grpc.ChainUnaryInterceptor(grpcauth.UnaryServerInterceptor(verifier)),
+//	    grpc.ChainStreamInterceptor(grpcauth.StreamServerInterceptor(verifier)),
+//	)
+//
+// Verification failures are returned as gRPC status errors: PermissionDenied
+// for an unverified email, Unavailable when the signing keys cannot be
+// fetched, and Unauthenticated for everything else, such as missing, invalid
+// or expired tokens and issuer or audience mismatches.
+package grpcauth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	setto "github.com/setto-labs/setto-server-sdk/go"
+)
+
+const defaultMetadataKey = "authorization"
+
+// Option configures the interceptors.
+type Option func(*options)
+
+type options struct {
+	requireEmail bool
+	metadataKey  string
+	skipMethods  map[string]bool
+}
+
+// WithRequireVerifiedEmail verifies tokens with VerifyIDTokenRequireEmail,
+// rejecting unverified emails with PermissionDenied.
+func WithRequireVerifiedEmail() Option {
+	return func(o *options) { o.requireEmail = true }
+}
+
+// WithMetadataKey sets the metadata key holding the token.
+// Defaults to "authorization". The "Bearer " prefix is optional.
+func WithMetadataKey(key string) Option {
+	return func(o *options) { o.metadataKey = strings.ToLower(key) }
+}
+
+// WithSkipMethods lets calls to the given full method names, such as
+// "/grpc.health.v1.Health/Check", through without a token.
+func WithSkipMethods(methods ...string) Option {
+	return func(o *options) {
+		for _, m := range methods {
+			o.skipMethods[m] = true
+		}
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{metadataKey: defaultMetadataKey, skipMethods: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// UnaryServerInterceptor returns a unary interceptor that authenticates each call.
+func UnaryServerInterceptor(verifier *setto.Verifier, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if o.skipMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		ctx, err := o.authenticate(ctx, verifier)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a stream interceptor that authenticates each stream.
+func StreamServerInterceptor(verifier *setto.Verifier, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if o.skipMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		ctx, err := o.authenticate(ss.Context(), verifier)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate verifies the token in ctx's metadata and returns a context
+// carrying the claims, or a status error.
+func (o *options) authenticate(ctx context.Context, verifier *setto.Verifier) (context.Context, error) {
+	token, ok := o.token(ctx)
+	if !ok {
+		return nil, statusFromError(ctx, setto.ErrMissingToken)
+	}
+
+	verify := verifier.VerifyIDToken
+	if o.requireEmail {
+		verify = verifier.VerifyIDTokenRequireEmail
+	}
+	claims, err := verify(ctx, token)
+	if err != nil {
+		return nil, statusFromError(ctx, err)
+	}
+	return setto.ContextWithClaims(ctx, claims), nil
+}
+
+func (o *options) token(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	values := md.Get(o.metadataKey)
+	if len(values) == 0 {
+		return "", false
+	}
+	token := values[0]
+	if scheme, rest, ok := strings.Cut(token, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		token = rest
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// statusFromError maps a verification error to a gRPC status error.
+func statusFromError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	switch {
+	case errors.Is(err, setto.ErrMissingToken):
+		return status.Error(codes.Unauthenticated, "missing ID token")
+	case errors.Is(err, setto.ErrTokenExpired):
+		return status.Error(codes.Unauthenticated, "ID token has expired")
+	case errors.Is(err, setto.ErrIssuerMismatch):
+		return status.Error(codes.Unauthenticated, "ID token issuer mismatch")
+	case errors.Is(err, setto.ErrEmailNotVerified):
+		return status.Error(codes.PermissionDenied, "email not verified")
+	case errors.Is(err, setto.ErrVerifierUnavailable):
+		return status.Error(codes.Unavailable, "ID token verifier unavailable")
+	default:
+		return status.Error(codes.Unauthenticated, "invalid ID token")
+	}
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,49 @@
+package setto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// WithIdempotencyKey sets the Idempotency-Key header for a mutating call.
+// The server returns the original result when the same key is replayed,
+// so a request that timed out can be retried without creating duplicates.
+// The key is reused unchanged across the SDK's own retries.
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *callOptions) { o.idempotencyKey = key }
+}
+
+// WithIdempotencyReference derives the Idempotency-Key from a caller-supplied
+// reference such as an order ID. The same reference always produces the same
+// key for the same endpoint, across retries and process restarts.
+// WithIdempotencyKey takes precedence if both are given.
+func WithIdempotencyReference(ref string) CallOption {
+	return func(o *callOptions) { o.idempotencyRef = ref }
+}
+
+// idempotencyKeyFor returns the key to send for a request, or "" if none.
+func (o *callOptions) idempotencyKeyFor(method, path string) string {
+	if o.idempotencyKey != "" {
+		return o.idempotencyKey
+	}
+	if o.idempotencyRef == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(method + " " + path + "\n" + o.idempotencyRef))
+	return hex.EncodeToString(sum[:16])
+}
+
+// isIdempotencyConflict reports whether the server rejected a request because
+// its Idempotency-Key was already used with a different request. Only a 409
+// without an error code is a conflict: a 409 carrying a system, payment or
+// validation code is an ordinary business error, and 422 is a validation
+// failure; both are left as is.
+func isIdempotencyConflict(req *http.Request, we *WalletError) bool {
+	if req.Header.Get(idempotencyKeyHeader) == "" {
+		return false
+	}
+	return we.HTTPStatus == http.StatusConflict && we.Code == ""
+}
@@ -9,7 +9,7 @@ import (
 // Used before store/merchant creation to enforce verification requirement.
 func (c *Client) GetVerificationStatus(ctx context.Context, userID string) (*VerificationStatus, error) {
 	var raw getVerificationStatusResponse
-	if err := c.do(ctx, "GET", "/api/integration/user/"+userID+"/verification", nil, &raw); err != nil {
+	if err := c.do(ctx, "GetVerificationStatus", "GET", "/api/integration/user/"+userID+"/verification", nil, &raw); err != nil {
 		return nil, fmt.Errorf("get verification status: %w", err)
 	}
 
@@ -22,11 +22,11 @@ func (c *Client) GetVerificationStatus(ctx context.Context, userID string) (*Ver
 // LinkAccountDirect performs S2S direct account linking via IdP token.
 // The IdP token is verified by setto-server which matches/creates the user.
 // No OTT intermediary required.
-func (c *Client) LinkAccountDirect(ctx context.Context, idToken string) (*AccountLinkDirectResult, error) {
+func (c *Client) LinkAccountDirect(ctx context.Context, idToken string, opts ...CallOption) (*AccountLinkDirectResult, error) {
 	reqBody := &linkAccountDirectRequest{IDToken: idToken}
 
 	var raw linkAccountDirectResponse
-	if err := c.do(ctx, "POST", "/api/integration/link-account-direct", reqBody, &raw); err != nil {
+	if err := c.do(ctx, "LinkAccountDirect", "POST", "/api/integration/link-account-direct", reqBody, &raw, opts...); err != nil {
 		return nil, fmt.Errorf("link account direct: %w", err)
 	}
 
@@ -38,11 +38,33 @@ func (c *Client) LinkAccountDirect(ctx context.Context, idToken string) (*Accoun
 	}, nil
 }
 
+// ExchangeAccountLinkToken exchanges a short-lived link token, issued to the
+// user by the Setto app, for the linked account's information.
+// A rejected token is reported as the server's *WalletError.
+func (c *Client) ExchangeAccountLinkToken(ctx context.Context, linkToken string, opts ...CallOption) (*AccountLinkInfo, error) {
+	if linkToken == "" {
+		return nil, fmt.Errorf("exchange account link token: %w", newLocalError(ValidationRequiredField, "link token is required"))
+	}
+
+	reqBody := &exchangeLinkTokenRequest{LinkToken: linkToken}
+
+	var raw accountLinkInfoWireResponse
+	if err := c.do(ctx, "ExchangeAccountLinkToken", "POST", "/api/integration/exchange-link-token", reqBody, &raw, opts...); err != nil {
+		return nil, fmt.Errorf("exchange account link token: %w", err)
+	}
+
+	return &AccountLinkInfo{
+		UserID:          raw.UserID,
+		Email:           raw.Email,
+		IsPhoneVerified: raw.IsPhoneVerified,
+	}, nil
+}
+
 // GetPayerProfile returns the payer's profile for a given payment.
 // Used by external integrations to display payer info (name, photo) without exposing email.
 func (c *Client) GetPayerProfile(ctx context.Context, paymentID string) (*PayerProfile, error) {
 	var raw getPayerProfileResponse
-	if err := c.do(ctx, "GET", "/api/external/payment/"+paymentID+"/payer", nil, &raw); err != nil {
+	if err := c.do(ctx, "GetPayerProfile", "GET", "/api/external/payment/"+paymentID+"/payer", nil, &raw); err != nil {
 		return nil, fmt.Errorf("get payer profile: %w", err)
 	}
 
@@ -57,7 +79,15 @@ func (c *Client) GetPayerProfile(ctx context.Context, paymentID string) (*PayerP
 // InitiatePayment creates a new payment session and returns payment information.
 // The server generates a payment_id (SSoT) and the SDK/client uses it to execute the payment.
 // Auth: X-API-Key (external integration)
-func (c *Client) InitiatePayment(ctx context.Context, req *InitiatePaymentRequest) (*InitiatePaymentResponse, error) {
+//
+// The request is checked with Validate before it is sent.
+// Pass WithIdempotencyKey or WithIdempotencyReference so that a retried call
+// returns the original payment instead of creating a second one.
+func (c *Client) InitiatePayment(ctx context.Context, req *InitiatePaymentRequest, opts ...CallOption) (*InitiatePaymentResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("initiate payment: %w", err)
+	}
+
 	wireReq := &initiatePaymentWireRequest{
 		MerchantID:      req.MerchantID,
 		Amount:          req.Amount,
@@ -68,7 +98,7 @@ func (c *Client) InitiatePayment(ctx context.Context, req *InitiatePaymentReques
 	}
 
 	var raw initiatePaymentWireResponse
-	if err := c.do(ctx, "POST", "/api/integration/payment/initiate", wireReq, &raw); err != nil {
+	if err := c.do(ctx, "InitiatePayment", "POST", "/api/integration/payment/initiate", wireReq, &raw, opts...); err != nil {
 		return nil, fmt.Errorf("initiate payment: %w", err)
 	}
 
@@ -0,0 +1,191 @@
+package setto
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+const redacted = "[REDACTED]"
+
+// WithLogger enables structured logging. Requests and responses are logged at
+// debug level; failures and retries at warn level. API keys, ID tokens and
+// email addresses are redacted, and bodies of types not known to be safe are
+// logged by type name only. Default: no logging.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *clientOptions) { o.logger = l }
+}
+
+// String returns "production" or "development".
+func (e Environment) String() string {
+	switch e {
+	case Production:
+		return "production"
+	case Development:
+		return "development"
+	}
+	return fmt.Sprintf("Environment(%d)", int(e))
+}
+
+// LogValue implements slog.LogValuer with the API key redacted.
+func (c Config) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("api_key", redactAPIKey(c.APIKey)),
+		slog.String("environment", c.Environment.String()),
+	}
+	if c.Credentials != nil {
+		attrs = append(attrs, slog.String("credentials", describeCredentials(c.Credentials)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Format implements fmt.Formatter so that the API key is redacted
+// for every verb, including %#v.
+func (c Config) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "setto.Config{APIKey:%s Environment:%s", redactAPIKey(c.APIKey), c.Environment)
+	if c.Credentials != nil {
+		fmt.Fprintf(f, " Credentials:%s", describeCredentials(c.Credentials))
+	}
+	fmt.Fprint(f, "}")
+}
+
+// LogValue implements slog.LogValuer with the API key redacted.
+func (c *Client) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("credentials", describeCredentials(c.credentials)),
+		slog.String("base_url", c.baseURL),
+	)
+}
+
+// Format implements fmt.Formatter so that the API key is redacted
+// for every verb, including %#v.
+func (c *Client) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "&setto.Client{credentials:%s baseURL:%s}", describeCredentials(c.credentials), c.baseURL)
+}
+
+// LogValue implements slog.LogValuer with the email redacted.
+func (r *AccountLinkDirectResult) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("user_id", r.UserID),
+		slog.String("email", redactEmail(r.Email)),
+		slog.Bool("is_phone_verified", r.IsPhoneVerified),
+		slog.Bool("is_new_user", r.IsNewUser),
+	)
+}
+
+// LogValue implements slog.LogValuer with the email redacted.
+func (r *AccountLinkInfo) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("user_id", r.UserID),
+		slog.String("email", redactEmail(r.Email)),
+		slog.Bool("is_phone_verified", r.IsPhoneVerified),
+	)
+}
+
+// LogValue implements slog.LogValuer with the email redacted.
+func (c *Claims) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("user_id", c.UserID),
+		slog.String("email", redactEmail(c.Email)),
+		slog.Bool("email_verified", c.EmailVerified),
+		slog.Time("issued_at", c.IssuedAt),
+		slog.Time("expires_at", c.ExpiresAt),
+	)
+}
+
+func (r *linkAccountDirectRequest) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id_token", redacted))
+}
+
+func (r *linkAccountDirectResponse) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("user_id", r.UserID),
+		slog.String("email", redactEmail(r.Email)),
+		slog.Bool("is_phone_verified", r.IsPhoneVerified),
+		slog.Bool("is_new_user", r.IsNewUser),
+	)
+}
+
+// redactAPIKey keeps the prefix and the last 4 characters of an API key.
+func redactAPIKey(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) < len(apiKeyPrefix)+8 {
+		return redacted
+	}
+	return apiKeyPrefix + "****" + key[len(key)-4:]
+}
+
+// redactEmail keeps the first character of the local part and the domain.
+// The character is decoded as a rune, so a multi-byte first letter is kept
+// whole rather than cut into invalid UTF-8.
+func redactEmail(email string) string {
+	if email == "" {
+		return ""
+	}
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return redacted
+	}
+	_, size := utf8.DecodeRuneInString(local)
+	return local[:size] + "***@" + domain
+}
+
+func (r *exchangeLinkTokenRequest) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("link_token", redacted))
+}
+
+func (r *accountLinkInfoWireResponse) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("user_id", r.UserID),
+		slog.String("email", redactEmail(r.Email)),
+		slog.Bool("is_phone_verified", r.IsPhoneVerified),
+	)
+}
+
+func (r *updateMerchantWireRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("name", r.Name),
+		slog.String("photo_url", r.PhotoURL),
+		slog.String("payout_evm_address", r.PayoutEVMAddress),
+		slog.String("payout_svm_address", r.PayoutSVMAddress),
+		slog.String("ott", redacted),
+	)
+}
+
+// logBody logs request bodies and results through an allowlist: values that
+// implement slog.LogValuer log themselves (redacting secrets), values marked
+// loggable are logged as is, and anything else is logged by type name only,
+// so a new body carrying a secret cannot leak by default.
+type logBody struct {
+	v any
+}
+
+// loggable marks wire types whose fields contain no secrets.
+type loggable interface {
+	loggable()
+}
+
+func (b logBody) LogValue() slog.Value {
+	switch v := b.v.(type) {
+	case nil:
+		return slog.AnyValue(nil)
+	case slog.LogValuer:
+		return v.LogValue()
+	case loggable:
+		return slog.AnyValue(v)
+	}
+	return slog.StringValue(fmt.Sprintf("%T %s", b.v, redacted))
+}
+
+func (*getVerificationStatusResponse) loggable()    {}
+func (*getPayerProfileResponse) loggable()          {}
+func (*PaymentInfo) loggable()                      {}
+func (*initiatePaymentWireRequest) loggable()       {}
+func (*initiatePaymentWireResponse) loggable()      {}
+func (*createMerchantWireRequest) loggable()        {}
+func (*createMerchantWireResponse) loggable()       {}
+func (*updateMerchantProfileWireRequest) loggable() {}
+func (*merchantWireResponse) loggable()             {}
@@ -0,0 +1,151 @@
+package setto
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// CreateMerchant creates a merchant with its payout wallet addresses.
+// The request is checked with Validate before it is sent.
+func (c *Client) CreateMerchant(ctx context.Context, req *CreateMerchantRequest, opts ...CallOption) (*CreateMerchantResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("create merchant: %w", err)
+	}
+
+	wireReq := &createMerchantWireRequest{
+		Name:             req.Name,
+		PhotoURL:         req.PhotoURL,
+		PayoutEVMAddress: req.PayoutEVMAddress,
+		PayoutSVMAddress: req.PayoutSVMAddress,
+		FeeRate:          req.FeeRate,
+	}
+
+	var raw createMerchantWireResponse
+	if err := c.do(ctx, "CreateMerchant", "POST", "/api/merchant", wireReq, &raw, opts...); err != nil {
+		return nil, fmt.Errorf("create merchant: %w", err)
+	}
+
+	return &CreateMerchantResponse{MerchantID: raw.MerchantID}, nil
+}
+
+// GetMerchant returns a merchant's details.
+// Returns PAYMENT_MERCHANT_NOT_FOUND if the merchant does not exist.
+func (c *Client) GetMerchant(ctx context.Context, merchantID string) (*GetMerchantResponse, error) {
+	if merchantID == "" {
+		return nil, fmt.Errorf("get merchant: %w", newLocalError(ValidationInvalidID, "merchant ID is required"))
+	}
+
+	var raw merchantWireResponse
+	if err := c.do(ctx, "GetMerchant", "GET", "/api/merchant/"+url.PathEscape(merchantID), nil, &raw); err != nil {
+		return nil, fmt.Errorf("get merchant: %w", err)
+	}
+
+	return &GetMerchantResponse{
+		MerchantID:       raw.MerchantID,
+		Name:             raw.Name,
+		PhotoURL:         raw.PhotoURL,
+		PayoutEVMAddress: raw.PayoutEVMAddress,
+		PayoutSVMAddress: raw.PayoutSVMAddress,
+	}, nil
+}
+
+// UpdateMerchant updates a merchant, including its payout wallet addresses.
+// Because it can redirect payouts, the server requires a One-Time Token with
+// scope UPDATE_MERCHANT, attached with WithOTT. A missing, expired or
+// mis-scoped token is reported as *OTTError without contacting the server.
+// Use UpdateMerchantProfile to change display info only.
+func (c *Client) UpdateMerchant(ctx context.Context, req *UpdateMerchantRequest, opts ...CallOption) (*UpdateMerchantResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("update merchant: %w", err)
+	}
+	ott, err := newCallOptions(opts).ottFor(OTTScopeUpdateMerchant, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("update merchant: %w", err)
+	}
+
+	wireReq := &updateMerchantWireRequest{
+		Name:             req.Name,
+		PhotoURL:         req.PhotoURL,
+		PayoutEVMAddress: req.PayoutEVMAddress,
+		PayoutSVMAddress: req.PayoutSVMAddress,
+		OTT:              ott,
+	}
+
+	var raw merchantWireResponse
+	if err := c.do(ctx, "UpdateMerchant", "PUT", "/api/merchant/"+url.PathEscape(req.MerchantID), wireReq, &raw, opts...); err != nil {
+		return nil, fmt.Errorf("update merchant: %w", err)
+	}
+
+	return &UpdateMerchantResponse{
+		MerchantID:       raw.MerchantID,
+		Name:             raw.Name,
+		PhotoURL:         raw.PhotoURL,
+		PayoutEVMAddress: raw.PayoutEVMAddress,
+		PayoutSVMAddress: raw.PayoutSVMAddress,
+	}, nil
+}
+
+// UpdateMerchantProfile updates a merchant's display name and photo.
+// No OTT is required.
+func (c *Client) UpdateMerchantProfile(ctx context.Context, req *UpdateMerchantProfileRequest, opts ...CallOption) (*GetMerchantResponse, error) {
+	if req.MerchantID == "" {
+		return nil, fmt.Errorf("update merchant profile: %w", newLocalError(ValidationInvalidID, "merchant ID is required"))
+	}
+
+	wireReq := &updateMerchantProfileWireRequest{
+		Name:     req.Name,
+		PhotoURL: req.PhotoURL,
+	}
+
+	var raw merchantWireResponse
+	path := "/api/merchant/" + url.PathEscape(req.MerchantID) + "/profile"
+	if err := c.do(ctx, "UpdateMerchantProfile", "PATCH", path, wireReq, &raw, opts...); err != nil {
+		return nil, fmt.Errorf("update merchant profile: %w", err)
+	}
+
+	return &GetMerchantResponse{
+		MerchantID:       raw.MerchantID,
+		Name:             raw.Name,
+		PhotoURL:         raw.PhotoURL,
+		PayoutEVMAddress: raw.PayoutEVMAddress,
+		PayoutSVMAddress: raw.PayoutSVMAddress,
+	}, nil
+}
+
+// Validate checks the request locally, reporting the same codes as the server.
+func (r *CreateMerchantRequest) Validate() error {
+	if r.Name == "" {
+		return newLocalError(PaymentMerchantNameRequired, "name is required")
+	}
+	if r.PayoutEVMAddress == "" {
+		return newLocalError(PaymentPayoutAddressRequired, "payout_evm_address is required")
+	}
+	if err := validatePayoutAddresses(r.PayoutEVMAddress, r.PayoutSVMAddress); err != nil {
+		return err
+	}
+	if r.FeeRate != "" && !decimalAmountPattern.MatchString(r.FeeRate) {
+		return newLocalError(ValidationInvalidFormat, fmt.Sprintf("fee_rate %q is not a decimal number", r.FeeRate))
+	}
+	return nil
+}
+
+// Validate checks the request locally, reporting the same codes as the server.
+func (r *UpdateMerchantRequest) Validate() error {
+	if r.MerchantID == "" {
+		return newLocalError(ValidationInvalidID, "merchant ID is required")
+	}
+	return validatePayoutAddresses(r.PayoutEVMAddress, r.PayoutSVMAddress)
+}
+
+// validatePayoutAddresses checks the payout addresses that are set.
+func validatePayoutAddresses(evm, svm string) error {
+	if evm != "" && !IsValidEVMAddress(evm) {
+		return newLocalError(PaymentInvalidEVMAddress, "payout_evm_address is not a valid EVM address")
+	}
+	if svm != "" && !IsValidSVMAddress(svm) {
+		return newLocalError(PaymentInvalidSVMAddress, "payout_svm_address is not a valid Solana address")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package setto
+
+import (
+	"context"
+	"net/http"
+)
+
+// Request describes a logical SDK call as seen by middleware.
+type Request struct {
+	Operation string      // Logical operation name, e.g. "InitiatePayment"
+	Method    string      // HTTP method
+	Path      string      // Request path relative to the base URL, including any query
+	Header    http.Header // Extra headers sent with every attempt; middleware may add to it
+	Body      interface{} // Request body before JSON encoding; nil for GET requests
+	Result    interface{} // Pointer the response is decoded into; populated once next returns
+}
+
+// Response describes how the server answered a call.
+type Response struct {
+	StatusCode int         // HTTP status of the last attempt
+	Header     http.Header // Response headers of the last attempt
+	Attempts   int         // Number of attempts made, including retries
+}
+
+// Handler executes an SDK call. The returned Response is nil if the server
+// was never reached. Errors from the server may be a bare *WalletError or a
+// typed error wrapping one, such as *OTTError or *IdempotencyConflictError;
+// other failures include *NetworkError, *ResponseTooLargeError and
+// ErrCircuitOpen. Inspect them with errors.As, errors.Is or IsWalletError
+// rather than a type assertion.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler to observe or modify SDK calls.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middleware that runs on every SDK call.
+// The first middleware given is the outermost one. Middleware wraps the whole
+// call, so a single invocation spans all of the SDK's retries.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(o *clientOptions) { o.middleware = append(o.middleware, mw...) }
+}
+
+// chain wraps h with mw so that mw[0] runs first.
+func chain(h Handler, mw []Middleware) Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
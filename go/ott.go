@@ -0,0 +1,67 @@
+package setto
+
+import (
+	"fmt"
+	"time"
+)
+
+// OTTScope is the operation a One-Time Token authorizes.
+type OTTScope string
+
+// One-Time Token scopes.
+const (
+	OTTScopeUpdateMerchant OTTScope = "UPDATE_MERCHANT"
+)
+
+// OTT is a One-Time Token authorizing a single sensitive operation, such as
+// changing a merchant's payout addresses. The merchant obtains it through the
+// Setto Wallet frontend SDK and hands it to your backend together with its
+// scope and expiry; the server accepts each token once. Setto's server API
+// has no endpoint for issuing OTTs, so this SDK carries tokens but cannot
+// obtain them.
+type OTT struct {
+	Token     string
+	Scope     OTTScope
+	ExpiresAt time.Time // Zero if unknown; the server still enforces expiry
+}
+
+// Expired reports whether the token has expired at now.
+func (t OTT) Expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+// WithOTT attaches a One-Time Token to a single call. Operations that require
+// one check its scope and expiry locally before contacting the server.
+func WithOTT(ott OTT) CallOption {
+	return func(o *callOptions) { o.ott = &ott }
+}
+
+// ottFor returns the token to send for an operation requiring scope, or an
+// *OTTError if none is attached or it cannot be used.
+func (o *callOptions) ottFor(scope OTTScope, now time.Time) (string, error) {
+	switch {
+	case o.ott == nil || o.ott.Token == "":
+		return "", newOTTError(PaymentOTTRequired, fmt.Sprintf("scope %s required", scope))
+	case o.ott.Scope != "" && o.ott.Scope != scope:
+		return "", newOTTError(PaymentOTTScopeMismatch, fmt.Sprintf("token has scope %s, need %s", o.ott.Scope, scope))
+	case o.ott.Expired(now):
+		return "", newOTTError(PaymentOTTExpired, fmt.Sprintf("token expired at %s", o.ott.ExpiresAt.Format(time.RFC3339)))
+	}
+	return o.ott.Token, nil
+}
+
+func newOTTError(code, detail string) *OTTError {
+	return &OTTError{Code: code, Cause: newLocalError(code, detail)}
+}
+
+// ottErrorFrom wraps a server error carrying an OTT code, or returns nil.
+func ottErrorFrom(err error) error {
+	we, ok := err.(*WalletError)
+	if !ok {
+		return nil
+	}
+	if _, ok := ottSentinels[we.PaymentError]; !ok {
+		return nil
+	}
+	return &OTTError{Code: we.PaymentError, Cause: we}
+}
@@ -8,7 +8,7 @@ import (
 // GetPaymentStatus retrieves the status of a payment.
 func (c *Client) GetPaymentStatus(ctx context.Context, paymentID string) (*PaymentInfo, error) {
 	var info PaymentInfo
-	if err := c.do(ctx, "GET", "/api/external/payment/"+paymentID, nil, &info); err != nil {
+	if err := c.do(ctx, "GetPaymentStatus", "GET", "/api/external/payment/"+paymentID, nil, &info); err != nil {
 		return nil, fmt.Errorf("get payment status: %w", err)
 	}
 	return &info, nil
@@ -0,0 +1,149 @@
+package setto
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits outgoing requests to rps requests per second,
+// allowing bursts of up to burst requests. The limit is shared by every
+// goroutine using the Client. Default: unlimited.
+//
+// When the server sends rate-limit headers (RateLimit-Remaining,
+// RateLimit-Reset or their X- prefixed forms) the limiter also holds
+// requests back until the server's window resets.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(o *clientOptions) {
+		if rps <= 0 {
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		o.limiter = newRateLimiter(rps, burst)
+	}
+}
+
+// WithMaxConcurrency caps the number of requests in flight at once.
+// Default: unlimited.
+func WithMaxConcurrency(n int) Option {
+	return func(o *clientOptions) {
+		if n > 0 {
+			o.maxConcurrency = n
+		}
+	}
+}
+
+// rateLimiter is a token bucket that can be paused by the server.
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64 // tokens per second
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.refill(now)
+
+		var d time.Duration
+		switch {
+		case now.Before(l.pausedUntil):
+			d = l.pausedUntil.Sub(now)
+		case l.tokens >= 1:
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		default:
+			d = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		}
+		l.mu.Unlock()
+
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *rateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	l.tokens += elapsed * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
+
+// observe adjusts the limiter from the server's rate-limit response headers.
+func (l *rateLimiter) observe(h http.Header, now time.Time) {
+	remaining, ok := headerInt(h, "RateLimit-Remaining", "X-RateLimit-Remaining")
+	reset := parseRetryAfter(h, now)
+	if !ok && reset == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	if ok && float64(remaining) < l.tokens {
+		l.tokens = float64(remaining)
+	}
+	if (ok && remaining == 0) || h.Get("Retry-After") != "" {
+		if until := now.Add(reset); until.After(l.pausedUntil) {
+			l.pausedUntil = until
+		}
+	}
+}
+
+// headerInt returns the first of the named headers that holds an integer.
+func headerInt(h http.Header, names ...string) (int64, bool) {
+	for _, name := range names {
+		if v := h.Get(name); v != "" {
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// acquire waits for the rate limiter and a concurrency slot.
+// The returned release function must be called once the request is done.
+func (c *Client) acquire(ctx context.Context) (release func(), err error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if c.sem == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.sem <- struct{}{}:
+		return func() { <-c.sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
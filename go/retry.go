@@ -0,0 +1,181 @@
+package setto
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryInitialDelay = 200 * time.Millisecond
+	defaultRetryMaxDelay     = 10 * time.Second
+	defaultRetryMultiplier   = 2.0
+	defaultRetryJitter       = 0.2
+)
+
+// RetryPolicy controls how the Client retries failed requests.
+//
+// Only idempotent requests are retried: GET requests, and POST requests that
+// carry an Idempotency-Key header. Network errors, SYSTEM_RATE_LIMITED and
+// SYSTEM_RPC_FAILED are considered retryable. Retries stop as soon as the
+// caller's context is cancelled.
+type RetryPolicy struct {
+	MaxAttempts  int           // Total attempts including the first one. <= 1 disables retries.
+	InitialDelay time.Duration // Delay before the first retry. Default: 200ms.
+	MaxDelay     time.Duration // Upper bound for any delay, including Retry-After. Default: 10s.
+	Multiplier   float64       // Backoff growth factor per attempt. Default: 2.
+	Jitter       float64       // Fraction of each delay that is randomized, in [0, 1]. Default: 0.2.
+}
+
+// DefaultRetryPolicy returns a policy with 3 attempts and the default backoff.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: defaultRetryInitialDelay,
+		MaxDelay:     defaultRetryMaxDelay,
+		Multiplier:   defaultRetryMultiplier,
+		Jitter:       defaultRetryJitter,
+	}
+}
+
+// WithRetry enables automatic retries. Default: no retries.
+// Zero-valued fields of p fall back to the defaults of DefaultRetryPolicy.
+func WithRetry(p RetryPolicy) Option {
+	return func(o *clientOptions) { o.retry = p.withDefaults() }
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = defaultRetryInitialDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultRetryMaxDelay
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaultRetryMultiplier
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = defaultRetryJitter
+	}
+	return p
+}
+
+// backoff returns the delay before the next attempt, given the number of
+// attempts made so far and the error of the last one.
+// A server-provided Retry-After takes precedence over a shorter backoff. If it
+// exceeds MaxDelay, backoff returns false and the request is not retried.
+func (p RetryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	d := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+	if d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	d -= d * p.Jitter * rand.Float64()
+	delay := time.Duration(d)
+
+	var walletErr *WalletError
+	if errors.As(err, &walletErr) && walletErr.RetryAfter > delay {
+		if walletErr.RetryAfter > p.MaxDelay {
+			return 0, false
+		}
+		delay = walletErr.RetryAfter
+	}
+	return delay, true
+}
+
+// isIdempotent reports whether a request may be safely sent more than once.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		return req.Header.Get(idempotencyKeyHeader) != ""
+	}
+	return false
+}
+
+// isRetryable reports whether err is a transient failure worth retrying.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var walletErr *WalletError
+	if errors.As(err, &walletErr) {
+		switch walletErr.Code {
+		case SystemRateLimited, SystemRPCFailed:
+			return true
+		}
+		switch walletErr.HTTPStatus {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+// isUnauthorized reports whether the server rejected the API key.
+func isUnauthorized(err error) bool {
+	var walletErr *WalletError
+	return errors.As(err, &walletErr) && walletErr.HTTPStatus == http.StatusUnauthorized
+}
+
+// sleepContext waits for d or until ctx is done.
+// It returns false without waiting if ctx would expire before d elapses.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// parseRetryAfter extracts the server-requested wait time from Retry-After
+// or the rate-limit reset headers. It returns 0 if none are present.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			if d := t.Sub(now); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+
+	for _, name := range []string{"RateLimit-Reset", "X-RateLimit-Reset"} {
+		v := h.Get(name)
+		if v == "" {
+			continue
+		}
+		secs, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || secs < 0 {
+			continue
+		}
+		// Some servers send an absolute Unix timestamp rather than a delta.
+		if secs > 1_000_000_000 {
+			if d := time.Unix(secs, 0).Sub(now); d > 0 {
+				return d
+			}
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
@@ -0,0 +1,226 @@
+package setto
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+const instrumentationName = "github.com/setto-labs/setto-server-sdk/go"
+
+// Span attribute keys set on SDK spans.
+const (
+	attrOperation      = attribute.Key("setto.operation")
+	attrPaymentID      = attribute.Key("setto.payment_id")
+	attrChainID        = attribute.Key("setto.chain_id")
+	attrErrorCode      = attribute.Key("setto.error_code")
+	attrAttempts       = attribute.Key("setto.attempts")
+	attrHTTPStatusCode = attribute.Key("http.response.status_code")
+	attrOutcome        = attribute.Key("setto.outcome")
+)
+
+// WithTracerProvider enables OpenTelemetry tracing of SDK calls.
+// Each call gets a span named after its operation, e.g. "setto.InitiatePayment",
+// and trace context is propagated in outgoing request headers.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *clientOptions) { o.tracerProvider = tp }
+}
+
+// WithMeterProvider enables OpenTelemetry metrics for SDK calls:
+// operation latency, error counts and, for verifiers created by the client,
+// JWKS refreshes.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(o *clientOptions) { o.meterProvider = mp }
+}
+
+// WithPropagator sets the propagator used to inject trace context into
+// outgoing requests. Default: the global propagator from otel.GetTextMapPropagator.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(o *clientOptions) { o.propagator = p }
+}
+
+// telemetry holds the OpenTelemetry instruments of a Client or Verifier.
+type telemetry struct {
+	tracer        trace.Tracer
+	propagator    propagation.TextMapPropagator
+	duration      metric.Float64Histogram
+	errors        metric.Int64Counter
+	jwksRefreshes metric.Int64Counter
+}
+
+// newTelemetry creates instruments from the given providers.
+// It returns nil if both providers are nil, which disables telemetry.
+func newTelemetry(tp trace.TracerProvider, mp metric.MeterProvider, prop propagation.TextMapPropagator) *telemetry {
+	if tp == nil && mp == nil {
+		return nil
+	}
+	if tp == nil {
+		tp = tracenoop.NewTracerProvider()
+	}
+	if mp == nil {
+		mp = metricnoop.NewMeterProvider()
+	}
+	if prop == nil {
+		prop = otel.GetTextMapPropagator()
+	}
+
+	meter := mp.Meter(instrumentationName, metric.WithInstrumentationVersion(sdkVersion))
+	t := &telemetry{
+		tracer:     tp.Tracer(instrumentationName, trace.WithInstrumentationVersion(sdkVersion)),
+		propagator: prop,
+	}
+
+	var err error
+	if t.duration, err = meter.Float64Histogram("setto.client.operation.duration",
+		metric.WithDescription("Duration of Setto SDK operations, including retries."),
+		metric.WithUnit("s")); err != nil {
+		otel.Handle(err)
+	}
+	if t.errors, err = meter.Int64Counter("setto.client.operation.errors",
+		metric.WithDescription("Number of failed Setto SDK operations."),
+		metric.WithUnit("{error}")); err != nil {
+		otel.Handle(err)
+	}
+	if t.jwksRefreshes, err = meter.Int64Counter("setto.verifier.jwks.refreshes",
+		metric.WithDescription("Number of JWKS fetches made by the ID token verifier."),
+		metric.WithUnit("{refresh}")); err != nil {
+		otel.Handle(err)
+	}
+	return t
+}
+
+// middleware returns a Middleware that traces and measures every call.
+func (t *telemetry) middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			start := time.Now()
+			ctx, span := t.tracer.Start(ctx, "setto."+req.Operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrOperation.String(req.Operation)),
+				trace.WithAttributes(requestAttributes(req)...),
+			)
+			defer span.End()
+
+			t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(ctx, req)
+
+			span.SetAttributes(resultAttributes(req)...)
+			if resp != nil {
+				span.SetAttributes(
+					attrHTTPStatusCode.Int(resp.StatusCode),
+					attrAttempts.Int(resp.Attempts),
+				)
+			}
+			t.end(ctx, span, start, req.Operation, err)
+			return resp, err
+		}
+	}
+}
+
+// end records the outcome of an operation on span and in the metrics.
+func (t *telemetry) end(ctx context.Context, span trace.Span, start time.Time, op string, err error) {
+	attrs := []attribute.KeyValue{attrOperation.String(op)}
+	if err != nil {
+		code := errorCode(err)
+		span.SetAttributes(attrErrorCode.String(code))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		attrs = append(attrs, attrOutcome.String("error"), attrErrorCode.String(code))
+		if t.errors != nil {
+			t.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+		}
+	} else {
+		attrs = append(attrs, attrOutcome.String("ok"))
+	}
+	if t.duration != nil {
+		t.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	}
+}
+
+// errorCode returns a low-cardinality code describing err.
+func errorCode(err error) string {
+	var walletErr *WalletError
+	var netErr *NetworkError
+	switch {
+	case errors.As(err, &walletErr) && walletErr.Code != "":
+		return walletErr.Code
+	case errors.As(err, &walletErr):
+		return http.StatusText(walletErr.HTTPStatus)
+	case errors.Is(err, ErrCircuitOpen):
+		return "CIRCUIT_OPEN"
+	case errors.As(err, &netErr):
+		return "NETWORK_ERROR"
+	case errors.Is(err, ErrVerifierUnavailable):
+		return "VERIFIER_UNAVAILABLE"
+	case errors.Is(err, ErrTokenExpired):
+		return "TOKEN_EXPIRED"
+	case errors.Is(err, ErrIssuerMismatch):
+		return "ISSUER_MISMATCH"
+	case errors.Is(err, ErrAudienceMismatch):
+		return "AUDIENCE_MISMATCH"
+	case errors.Is(err, ErrAZPMismatch):
+		return "AZP_MISMATCH"
+	case errors.Is(err, ErrTokenNotYetValid):
+		return "TOKEN_NOT_YET_VALID"
+	case errors.Is(err, ErrTokenTooOld):
+		return "TOKEN_TOO_OLD"
+	case errors.Is(err, ErrMissingClaim):
+		return "MISSING_CLAIM"
+	case errors.Is(err, ErrKeyNotFound):
+		return "KEY_NOT_FOUND"
+	case errors.Is(err, ErrEmailNotVerified):
+		return "EMAIL_NOT_VERIFIED"
+	case errors.Is(err, ErrTokenInvalid):
+		return "TOKEN_INVALID"
+	}
+	return "UNKNOWN"
+}
+
+// requestAttributes extracts span attributes from a request before it is sent.
+func requestAttributes(req *Request) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if id, ok := strings.CutPrefix(req.Path, "/api/external/payment/"); ok {
+		id, _, _ = strings.Cut(id, "/")
+		attrs = append(attrs, attrPaymentID.String(id))
+	}
+	if body, ok := req.Body.(*initiatePaymentWireRequest); ok {
+		attrs = append(attrs, attrChainID.Int(int(body.ChainID)))
+	}
+	return attrs
+}
+
+// resultAttributes extracts span attributes from a decoded response.
+func resultAttributes(req *Request) []attribute.KeyValue {
+	if result, ok := req.Result.(*initiatePaymentWireResponse); ok && result.PaymentID != "" {
+		return []attribute.KeyValue{attrPaymentID.String(result.PaymentID)}
+	}
+	return nil
+}
+
+// jwksMetricsTransport counts JWKS fetches.
+type jwksMetricsTransport struct {
+	base      http.RoundTripper
+	refreshes metric.Int64Counter
+}
+
+func (t *jwksMetricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	outcome := "ok"
+	if err != nil || resp.StatusCode >= 300 {
+		outcome = "error"
+	}
+	t.refreshes.Add(req.Context(), 1, metric.WithAttributes(attrOutcome.String(outcome)))
+	return resp, err
+}
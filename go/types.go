@@ -29,6 +29,13 @@ type AccountLinkDirectResult struct {
 	IsNewUser       bool
 }
 
+// AccountLinkInfo holds the account information returned by a link-token exchange.
+type AccountLinkInfo struct {
+	UserID          string
+	Email           string
+	IsPhoneVerified bool
+}
+
 // ---- Profile types ----
 
 // PayerProfile holds the payer's profile for a payment.
@@ -81,6 +88,16 @@ type linkAccountDirectResponse struct {
 	IsNewUser       bool   `json:"is_new_user"`
 }
 
+type exchangeLinkTokenRequest struct {
+	LinkToken string `json:"link_token"`
+}
+
+type accountLinkInfoWireResponse struct {
+	UserID          string `json:"user_id"`
+	Email           string `json:"email"`
+	IsPhoneVerified bool   `json:"is_phone_verified"`
+}
+
 type getPayerProfileResponse struct {
 	SettoID     string `json:"setto_id"`
 	DisplayName string `json:"display_name"`
@@ -108,11 +125,11 @@ type InitiatePaymentResponse struct {
 	Amount          string `json:"amount"`
 	ChainID         int32  `json:"chain_id"`
 	ContractAddress string `json:"contract_address"`
-	ExpiresAt       int64  `json:"expires_at"`
-	CreatedAt       int64  `json:"created_at"`
+	ExpiresAt       int64  `json:"expires_at"` // Unix ms
+	CreatedAt       int64  `json:"created_at"` // Unix ms
 	FeeAmount       string `json:"fee_amount"`
 	MerchantAddress string `json:"merchant_address,omitempty"`
-	Deadline        int64  `json:"deadline,omitempty"`
+	Deadline        int64  `json:"deadline,omitempty"` // Unix ms
 }
 
 type initiatePaymentWireRequest struct {
@@ -137,3 +154,92 @@ type initiatePaymentWireResponse struct {
 	MerchantAddress string `json:"merchant_address,omitempty"`
 	Deadline        int64  `json:"deadline,omitempty"`
 }
+
+// ---- Merchant types ----
+
+// CreateMerchantRequest is the request for creating a merchant.
+type CreateMerchantRequest struct {
+	Name             string // Required. Display name
+	PhotoURL         string // Optional. Display photo
+	PayoutEVMAddress string // Required. Payout wallet on EVM chains
+	PayoutSVMAddress string // Optional. Payout wallet on Solana
+	FeeRate          string // Optional. Decimal fee rate, e.g. "0.015"
+}
+
+// CreateMerchantResponse is the response from merchant creation.
+type CreateMerchantResponse struct {
+	MerchantID string
+}
+
+// GetMerchantResponse holds a merchant's details.
+type GetMerchantResponse struct {
+	MerchantID       string
+	Name             string
+	PhotoURL         string
+	PayoutEVMAddress string
+	PayoutSVMAddress string
+}
+
+// UpdateMerchantRequest updates a merchant, including its payout wallet
+// addresses. Empty fields are left unchanged.
+// Requires a One-Time Token with scope UPDATE_MERCHANT; see WithOTT.
+type UpdateMerchantRequest struct {
+	MerchantID       string
+	Name             string
+	PhotoURL         string
+	PayoutEVMAddress string
+	PayoutSVMAddress string
+}
+
+// UpdateMerchantResponse holds the merchant's details after an update.
+type UpdateMerchantResponse struct {
+	MerchantID       string
+	Name             string
+	PhotoURL         string
+	PayoutEVMAddress string
+	PayoutSVMAddress string
+}
+
+// UpdateMerchantProfileRequest updates a merchant's display info only.
+// Empty fields are left unchanged. No OTT is required.
+type UpdateMerchantProfileRequest struct {
+	MerchantID string
+	Name       string
+	PhotoURL   string
+}
+
+type createMerchantWireRequest struct {
+	Name             string `json:"name"`
+	PhotoURL         string `json:"photo_url,omitempty"`
+	PayoutEVMAddress string `json:"payout_evm_address"`
+	PayoutSVMAddress string `json:"payout_svm_address,omitempty"`
+	FeeRate          string `json:"fee_rate,omitempty"`
+}
+
+type createMerchantWireResponse struct {
+	MerchantID string `json:"merchant_id"`
+}
+
+// updateMerchantWireRequest carries the OTT in the JSON field "ott". Setto's
+// API documentation requires an OTT for this call but does not say how it is
+// transmitted; the field name is an assumption until that is published.
+type updateMerchantWireRequest struct {
+	Name             string `json:"name,omitempty"`
+	PhotoURL         string `json:"photo_url,omitempty"`
+	PayoutEVMAddress string `json:"payout_evm_address,omitempty"`
+	PayoutSVMAddress string `json:"payout_svm_address,omitempty"`
+	OTT              string `json:"ott"`
+}
+
+type updateMerchantProfileWireRequest struct {
+	Name     string `json:"name,omitempty"`
+	PhotoURL string `json:"photo_url,omitempty"`
+}
+
+type merchantWireResponse struct {
+	MerchantID       string `json:"merchant_id"`
+	Name             string `json:"name"`
+	PhotoURL         string `json:"photo_url"`
+	PayoutEVMAddress string `json:"payout_evm_address"`
+	PayoutSVMAddress string `json:"payout_svm_address"`
+}
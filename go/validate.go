@@ -0,0 +1,139 @@
+package setto
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+var decimalAmountPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// Validate checks the request locally before it is sent. It reports the same
+// codes the server would, as a *WalletError with HTTPStatus 0, so callers can
+// handle local and remote validation failures the same way.
+// InitiatePayment calls Validate automatically.
+func (r *InitiatePaymentRequest) Validate() error {
+	if r.MerchantID == "" {
+		return newLocalError(ValidationRequiredField, "merchant_id is required")
+	}
+	if r.SettoUserID == "" {
+		return newLocalError(ValidationRequiredField, "setto_user_id is required")
+	}
+	if err := validateAmount(r.Amount); err != nil {
+		return err
+	}
+
+	// An empty contract address pays in the chain's native token. Chains this
+	// package does not know about are left to the server.
+	if r.ContractAddress == "" {
+		return nil
+	}
+	switch ChainTypeOf(r.ChainID) {
+	case ChainTypeEVM:
+		if !IsValidEVMAddress(r.ContractAddress) {
+			return newLocalError(ValidationInvalidAddress, "contract_address is not a valid EVM address")
+		}
+	case ChainTypeSVM:
+		if !IsValidSVMAddress(r.ContractAddress) {
+			return newLocalError(ValidationInvalidAddress, "contract_address is not a valid Solana address")
+		}
+	}
+	return nil
+}
+
+// validateAmount checks that amount is a positive decimal string.
+func validateAmount(amount string) error {
+	if amount == "" {
+		return newLocalError(PaymentAmountRequired, "amount is required")
+	}
+	if !decimalAmountPattern.MatchString(amount) {
+		return newLocalError(PaymentAmountInvalidFormat, fmt.Sprintf("amount %q is not a decimal number", amount))
+	}
+	if strings.Trim(amount, "0.") == "" {
+		return newLocalError(PaymentAmountTooLow, "amount must be greater than zero")
+	}
+	return nil
+}
+
+// IsValidEVMAddress reports whether addr is a 0x-prefixed 20-byte hex address.
+// Mixed-case addresses must carry a valid EIP-55 checksum; all-lowercase and
+// all-uppercase addresses are accepted without one.
+func IsValidEVMAddress(addr string) bool {
+	if len(addr) != 42 || !strings.HasPrefix(addr, "0x") {
+		return false
+	}
+	body := addr[2:]
+	if _, err := hex.DecodeString(body); err != nil {
+		return false
+	}
+	if body == strings.ToLower(body) || body == strings.ToUpper(body) {
+		return true
+	}
+	return addr == toChecksumAddress(body)
+}
+
+// toChecksumAddress applies EIP-55 mixed-case checksum encoding to a hex address body.
+func toChecksumAddress(body string) string {
+	lower := strings.ToLower(body)
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	out := []byte(lower)
+	for i, c := range out {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// IsValidSVMAddress reports whether addr is a base58-encoded 32-byte Solana public key.
+func IsValidSVMAddress(addr string) bool {
+	if len(addr) < 32 || len(addr) > 44 {
+		return false
+	}
+	decoded, ok := decodeBase58(addr)
+	return ok && len(decoded) == 32
+}
+
+// decodeBase58 decodes a Bitcoin-alphabet base58 string.
+func decodeBase58(s string) ([]byte, bool) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range s {
+		i := strings.IndexRune(base58Alphabet, c)
+		if i < 0 {
+			return nil, false
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+
+	leadingZeros := 0
+	for leadingZeros < len(s) && s[leadingZeros] == '1' {
+		leadingZeros++
+	}
+	return append(make([]byte, leadingZeros), n.Bytes()...), true
+}
+
+// newLocalError builds a WalletError for a failure detected before sending.
+// The code is placed in the same category field the server would use.
+func newLocalError(code, detail string) *WalletError {
+	we := &WalletError{Code: code, Message: fmt.Sprintf("setto: %s: %s", code, detail)}
+	switch {
+	case strings.HasPrefix(code, "VALIDATION_"):
+		we.ValidationError = code
+	case strings.HasPrefix(code, "PAYMENT_"):
+		we.PaymentError = code
+	case strings.HasPrefix(code, "SYSTEM_"):
+		we.SystemError = code
+	}
+	return we
+}
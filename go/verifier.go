@@ -4,58 +4,204 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/lestrrat-go/jwx/v2/jwk"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Verifier verifies Setto Wallet ID Tokens using JWKS.
 // Thread-safe and handles JWKS caching/refresh per OIDC standard.
 type Verifier struct {
-	jwksURL string
-	issuer  string
+	jwksURL    string
+	issuer     string
+	httpClient *http.Client
+	telemetry  *telemetry
+	logger     *slog.Logger
+
+	allowedAlgorithms []string // nil to accept what the keys advertise
+	rules             claimRules
 
 	mu        sync.RWMutex
 	cache     *jwk.Cache
 	cachedSet jwk.Set
 	cacheCtx  context.Context
+
+	// Set by NewVerifierFromDiscovery. jwksURL and algorithms are replaced
+	// under mu when the discovery document is re-read.
+	discoveryURL      string
+	discoveryClient   *http.Client
+	discoveryInterval time.Duration
+	discoveryMu       sync.Mutex // held while re-reading the document
+	nextDiscovery     time.Time
+	discoveryFailures int
+	algorithms        []string
+}
+
+// VerifierOption configures the Verifier.
+type VerifierOption func(*verifierOptions)
+
+type verifierOptions struct {
+	httpClient     *http.Client
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	logger         *slog.Logger
+
+	allowedAlgorithms []string
+	rules             claimRules
+	discoveryInterval time.Duration
+}
+
+// WithJWKSHTTPClient sets the http.Client used to fetch the JWKS and, for
+// NewVerifierFromDiscovery, the discovery document.
+func WithJWKSHTTPClient(c *http.Client) VerifierOption {
+	return func(o *verifierOptions) { o.httpClient = c }
+}
+
+// WithVerifierTracerProvider enables a "setto.VerifyIDToken" span per verification.
+func WithVerifierTracerProvider(tp trace.TracerProvider) VerifierOption {
+	return func(o *verifierOptions) { o.tracerProvider = tp }
+}
+
+// WithVerifierMeterProvider enables verification latency, error and JWKS refresh metrics.
+func WithVerifierMeterProvider(mp metric.MeterProvider) VerifierOption {
+	return func(o *verifierOptions) { o.meterProvider = mp }
+}
+
+// WithVerifierLogger logs verification results: successes at debug level and
+// failures at warn level. Tokens are never logged and emails are redacted.
+func WithVerifierLogger(l *slog.Logger) VerifierOption {
+	return func(o *verifierOptions) { o.logger = l }
 }
 
 // NewVerifier creates a new Wallet ID Token verifier.
 // JWKS is NOT fetched at this point; it's fetched lazily on first VerifyIDToken call.
-func NewVerifier(jwksURL, issuer string) *Verifier {
-	return &Verifier{
-		jwksURL: jwksURL,
-		issuer:  issuer,
+func NewVerifier(jwksURL, issuer string, opts ...VerifierOption) *Verifier {
+	options := &verifierOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	v := &Verifier{
+		jwksURL:    jwksURL,
+		issuer:     issuer,
+		httpClient: options.httpClient,
+		telemetry:  newTelemetry(options.tracerProvider, options.meterProvider, nil),
+		logger:     options.logger,
+
+		allowedAlgorithms: options.allowedAlgorithms,
+		rules:             options.rules,
+	}
+	if v.logger == nil {
+		v.logger = slog.New(slog.DiscardHandler)
+	}
+
+	if v.telemetry != nil {
+		httpClient := http.Client{}
+		if v.httpClient != nil {
+			httpClient = *v.httpClient
+		}
+		base := httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		httpClient.Transport = &jwksMetricsTransport{base: base, refreshes: v.telemetry.jwksRefreshes}
+		v.httpClient = &httpClient
 	}
+
+	return v
 }
 
 // VerifyIDToken verifies a Wallet ID Token and returns the claims.
+// The token's alg must be allowed (see WithAllowedAlgorithms) and match the
+// type, curve and advertised alg of the JWKS key named by its kid.
 // JWKS is fetched lazily and cached. If kid is not found, JWKS is re-fetched.
-func (v *Verifier) VerifyIDToken(ctx context.Context, idToken string) (*Claims, error) {
+func (v *Verifier) VerifyIDToken(ctx context.Context, idToken string) (claims *Claims, err error) {
+	defer func() {
+		if err != nil {
+			v.logger.LogAttrs(ctx, slog.LevelWarn, "setto: ID token verification failed", slog.Any("error", err))
+		} else {
+			v.logger.LogAttrs(ctx, slog.LevelDebug, "setto: ID token verified", slog.Any("claims", claims))
+		}
+	}()
+
+	if v.telemetry == nil {
+		return v.verifyIDToken(ctx, idToken)
+	}
+
+	start := time.Now()
+	ctx, span := v.telemetry.tracer.Start(ctx, "setto.VerifyIDToken",
+		trace.WithAttributes(attrOperation.String("VerifyIDToken")))
+	defer span.End()
+
+	claims, err = v.verifyIDToken(ctx, idToken)
+	v.telemetry.end(ctx, span, start, "VerifyIDToken", err)
+	return claims, err
+}
+
+func (v *Verifier) verifyIDToken(ctx context.Context, idToken string) (*Claims, error) {
+	v.maybeRefreshDiscovery(ctx)
+
 	if err := v.ensureCache(ctx); err != nil {
-		return nil, fmt.Errorf("failed to initialize JWKS cache: %w", err)
+		return nil, fmt.Errorf("%w: initialize JWKS cache: %w", ErrVerifierUnavailable, err)
+	}
+
+	v.mu.RLock()
+	issuer, algorithms := v.issuer, v.algorithms
+	v.mu.RUnlock()
+	if len(v.allowedAlgorithms) > 0 {
+		algorithms = v.allowedAlgorithms
 	}
 
+	var unavailable error
 	token, err := jwt.Parse(idToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		alg := token.Method.Alg()
+		if !isSupportedAlgorithm(alg) {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		if len(algorithms) > 0 && !slices.Contains(algorithms, alg) {
+			return nil, fmt.Errorf("signing method %s is not allowed", alg)
+		}
 
 		kid, ok := token.Header["kid"].(string)
 		if !ok {
 			return nil, errors.New("kid not found in token header")
 		}
 
-		return v.getKeyByKid(ctx, kid)
-	})
+		key, err := v.getKeyByKid(ctx, kid)
+		if err != nil {
+			if errors.Is(err, ErrVerifierUnavailable) {
+				unavailable = err
+			}
+			return nil, err
+		}
+		if err := checkKeyAlgorithm(key, alg); err != nil {
+			return nil, err
+		}
+
+		var rawKey interface{}
+		if err := key.Raw(&rawKey); err != nil {
+			return nil, fmt.Errorf("failed to get raw key: %w", err)
+		}
+		return rawKey, nil
+	}, jwt.WithLeeway(v.rules.leeway))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrTokenExpired
 		}
+		if errors.Is(err, jwt.ErrTokenNotValidYet) {
+			return nil, ErrTokenNotYetValid
+		}
+		if unavailable != nil {
+			return nil, unavailable
+		}
 		return nil, fmt.Errorf("%w: %v", ErrTokenInvalid, err)
 	}
 
@@ -69,8 +215,11 @@ func (v *Verifier) VerifyIDToken(ctx context.Context, idToken string) (*Claims,
 	}
 
 	iss, _ := mapClaims["iss"].(string)
-	if iss != v.issuer {
-		return nil, fmt.Errorf("%w: expected %s, got %s", ErrIssuerMismatch, v.issuer, iss)
+	if iss != issuer {
+		return nil, fmt.Errorf("%w: expected %s, got %s", ErrIssuerMismatch, issuer, iss)
+	}
+	if err := v.rules.check(mapClaims, time.Now()); err != nil {
+		return nil, err
 	}
 
 	claims := &Claims{}
@@ -122,41 +271,40 @@ func (v *Verifier) ensureCache(ctx context.Context) error {
 
 	v.cacheCtx = context.Background()
 	v.cache = jwk.NewCache(v.cacheCtx)
-	v.cache.Register(v.jwksURL, jwk.WithMinRefreshInterval(15*time.Minute))
+	v.cache.Register(v.jwksURL, v.registerOptions()...)
 	v.cachedSet = jwk.NewCachedSet(v.cache, v.jwksURL)
 
 	return nil
 }
 
-func (v *Verifier) getKeyByKid(ctx context.Context, kid string) (interface{}, error) {
+func (v *Verifier) registerOptions() []jwk.RegisterOption {
+	opts := []jwk.RegisterOption{jwk.WithMinRefreshInterval(15 * time.Minute)}
+	if v.httpClient != nil {
+		opts = append(opts, jwk.WithHTTPClient(v.httpClient))
+	}
+	return opts
+}
+
+func (v *Verifier) getKeyByKid(ctx context.Context, kid string) (jwk.Key, error) {
 	v.mu.RLock()
 	cachedSet := v.cachedSet
 	cache := v.cache
+	jwksURL := v.jwksURL
 	v.mu.RUnlock()
 
 	key, found := cachedSet.LookupKeyID(kid)
 	if found {
-		var rawKey interface{}
-		if err := key.Raw(&rawKey); err != nil {
-			return nil, fmt.Errorf("failed to get raw key: %w", err)
-		}
-		return rawKey, nil
+		return key, nil
 	}
 
-	_, err := cache.Refresh(ctx, v.jwksURL)
+	_, err := cache.Refresh(ctx, jwksURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to refresh JWKS: %w", err)
+		return nil, fmt.Errorf("%w: refresh JWKS: %w", ErrVerifierUnavailable, err)
 	}
 
 	key, found = cachedSet.LookupKeyID(kid)
 	if !found {
 		return nil, ErrKeyNotFound
 	}
-
-	var rawKey interface{}
-	if err := key.Raw(&rawKey); err != nil {
-		return nil, fmt.Errorf("failed to get raw key: %w", err)
-	}
-
-	return rawKey, nil
+	return key, nil
 }
@@ -0,0 +1,137 @@
+package setto
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	defaultPendingPollInterval   = 3 * time.Second
+	defaultSubmittedPollInterval = time.Second
+)
+
+// WaitOption configures WaitForPayment.
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	pendingInterval   time.Duration
+	submittedInterval time.Duration
+	deadline          time.Time
+	onTransition      func(from, to PaymentStatus, info *PaymentInfo)
+}
+
+// WithPollIntervals sets how often the status is polled while the payment is
+// pending and after it has been submitted on-chain. Default: 3s and 1s.
+func WithPollIntervals(pending, submitted time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		if pending > 0 {
+			o.pendingInterval = pending
+		}
+		if submitted > 0 {
+			o.submittedInterval = submitted
+		}
+	}
+}
+
+// WithWaitDeadline stops waiting at t with a *PaymentTimeoutError.
+func WithWaitDeadline(t time.Time) WaitOption {
+	return func(o *waitOptions) { o.deadline = t }
+}
+
+// WithPaymentDeadline stops waiting once the payment can no longer complete:
+// at the later of the ExpiresAt and Deadline returned by InitiatePayment.
+func WithPaymentDeadline(resp *InitiatePaymentResponse) WaitOption {
+	return func(o *waitOptions) {
+		deadline := unixTime(resp.ExpiresAt)
+		if d := unixTime(resp.Deadline); d.After(deadline) {
+			deadline = d
+		}
+		o.deadline = deadline
+	}
+}
+
+// WithStatusCallback registers fn to be called whenever the observed status
+// changes. The first observation is reported with an empty from status.
+func WithStatusCallback(fn func(from, to PaymentStatus, info *PaymentInfo)) WaitOption {
+	return func(o *waitOptions) { o.onTransition = fn }
+}
+
+// WaitForPayment polls GetPaymentStatus until the payment is complete or
+// failed, and returns the final PaymentInfo. Check the result with
+// IsPaymentComplete or IsPaymentFailed.
+//
+// Polling is faster while the payment is submitted than while it is pending.
+// Transient errors (see WithRetry) are tolerated and polling continues.
+// If the wait deadline or ctx's deadline passes first, a *PaymentTimeoutError
+// is returned holding the last observed status.
+func (c *Client) WaitForPayment(ctx context.Context, paymentID string, opts ...WaitOption) (*PaymentInfo, error) {
+	options := &waitOptions{
+		pendingInterval:   defaultPendingPollInterval,
+		submittedInterval: defaultSubmittedPollInterval,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if !options.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, options.deadline)
+		defer cancel()
+	}
+
+	var last *PaymentInfo
+	for {
+		info, err := c.GetPaymentStatus(ctx, paymentID)
+		switch {
+		case err == nil:
+			if last == nil || info.Status != last.Status {
+				var from PaymentStatus
+				if last != nil {
+					from = last.Status
+				}
+				if options.onTransition != nil {
+					options.onTransition(from, info.Status, info)
+				}
+			}
+			last = info
+			if info.IsPaymentComplete() || info.IsPaymentFailed() {
+				return info, nil
+			}
+		case ctx.Err() != nil:
+			return nil, waitError(ctx, paymentID, last)
+		case !isRetryable(err):
+			return nil, err
+		}
+
+		interval := options.pendingInterval
+		if last != nil && last.Status == PaymentStatusSubmitted {
+			interval = options.submittedInterval
+		}
+
+		t := time.NewTimer(interval)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return nil, waitError(ctx, paymentID, last)
+		}
+	}
+}
+
+// waitError converts the end of a wait into the error returned to the caller.
+func waitError(ctx context.Context, paymentID string, last *PaymentInfo) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &PaymentTimeoutError{PaymentID: paymentID, Last: last}
+	}
+	return ctx.Err()
+}
+
+// unixTime converts a server timestamp, in Unix milliseconds like every
+// timestamp the server returns, to time.Time. Zero maps to the zero time.
+func unixTime(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
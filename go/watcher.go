@@ -0,0 +1,236 @@
+package setto
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultWatchInterval    = 3 * time.Second
+	defaultWatchConcurrency = 8
+	defaultWatchBuffer      = 64
+)
+
+// PaymentTransition describes a status change of a watched payment.
+type PaymentTransition struct {
+	From PaymentStatus // Empty on the first observation of a payment
+	To   PaymentStatus
+	Info *PaymentInfo
+}
+
+// WatcherOption configures a PaymentWatcher.
+type WatcherOption func(*watcherOptions)
+
+type watcherOptions struct {
+	interval     time.Duration
+	concurrency  int
+	bufferSize   int
+	onTransition func(PaymentTransition)
+	onError      func(paymentID string, err error)
+}
+
+// WithWatchInterval sets how often every watched payment is polled. Default: 3s.
+func WithWatchInterval(d time.Duration) WatcherOption {
+	return func(o *watcherOptions) {
+		if d > 0 {
+			o.interval = d
+		}
+	}
+}
+
+// WithWatchConcurrency caps the number of concurrent GetPaymentStatus calls. Default: 8.
+func WithWatchConcurrency(n int) WatcherOption {
+	return func(o *watcherOptions) {
+		if n > 0 {
+			o.concurrency = n
+		}
+	}
+}
+
+// WithWatchBuffer sets the buffer size of the Events channel. Default: 64.
+func WithWatchBuffer(n int) WatcherOption {
+	return func(o *watcherOptions) {
+		if n >= 0 {
+			o.bufferSize = n
+		}
+	}
+}
+
+// WithTransitionCallback delivers transitions to fn instead of the Events
+// channel. fn is called from polling goroutines and must be safe for
+// concurrent use.
+func WithTransitionCallback(fn func(PaymentTransition)) WatcherOption {
+	return func(o *watcherOptions) { o.onTransition = fn }
+}
+
+// WithWatchErrorHandler registers fn to be called when polling a payment fails.
+// The payment stays watched unless the server reports PAYMENT_NOT_FOUND.
+func WithWatchErrorHandler(fn func(paymentID string, err error)) WatcherOption {
+	return func(o *watcherOptions) { o.onError = fn }
+}
+
+// PaymentWatcher polls many payments on a shared schedule and emits a
+// PaymentTransition whenever one changes status. Payments are dropped
+// automatically once they are complete or failed.
+type PaymentWatcher struct {
+	client  *Client
+	options *watcherOptions
+	events  chan PaymentTransition
+	started atomic.Bool
+
+	mu       sync.Mutex
+	payments map[string]PaymentStatus // last observed status, "" if none yet
+}
+
+// NewPaymentWatcher creates a watcher that polls through this client.
+// Call Run to start polling.
+func (c *Client) NewPaymentWatcher(opts ...WatcherOption) *PaymentWatcher {
+	options := &watcherOptions{
+		interval:    defaultWatchInterval,
+		concurrency: defaultWatchConcurrency,
+		bufferSize:  defaultWatchBuffer,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	w := &PaymentWatcher{
+		client:   c,
+		options:  options,
+		payments: make(map[string]PaymentStatus),
+	}
+	if options.onTransition == nil {
+		w.events = make(chan PaymentTransition, options.bufferSize)
+	}
+	return w
+}
+
+// Watch starts tracking paymentID. Watching an already tracked payment is a no-op.
+func (w *PaymentWatcher) Watch(paymentID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.payments[paymentID]; !ok {
+		w.payments[paymentID] = ""
+	}
+}
+
+// Unwatch stops tracking paymentID.
+func (w *PaymentWatcher) Unwatch(paymentID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.payments, paymentID)
+}
+
+// Len returns the number of payments being watched.
+func (w *PaymentWatcher) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.payments)
+}
+
+// Events returns the channel transitions are delivered on. It is closed when
+// Run returns, and is nil if WithTransitionCallback was used.
+// A full channel pauses polling until the consumer catches up.
+func (w *PaymentWatcher) Events() <-chan PaymentTransition {
+	return w.events
+}
+
+// Run polls the watched payments until ctx is done, then returns ctx.Err().
+// A watcher runs only once: later calls return an error immediately.
+func (w *PaymentWatcher) Run(ctx context.Context) error {
+	if w.started.Swap(true) {
+		return errors.New("setto: payment watcher has already been run")
+	}
+	if w.events != nil {
+		defer close(w.events)
+	}
+
+	ticker := time.NewTicker(w.options.interval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// poll checks every watched payment once, with bounded concurrency.
+func (w *PaymentWatcher) poll(ctx context.Context) {
+	w.mu.Lock()
+	ids := make([]string, 0, len(w.payments))
+	for id := range w.payments {
+		ids = append(ids, id)
+	}
+	w.mu.Unlock()
+
+	sem := make(chan struct{}, w.options.concurrency)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			w.check(ctx, id)
+		}(id)
+	}
+	wg.Wait()
+}
+
+// check polls a single payment and emits a transition if its status changed.
+func (w *PaymentWatcher) check(ctx context.Context, paymentID string) {
+	info, err := w.client.GetPaymentStatus(ctx, paymentID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		if walletErr, ok := IsWalletError(err); ok && walletErr.Code == PaymentNotFound {
+			w.Unwatch(paymentID)
+		}
+		if w.options.onError != nil {
+			w.options.onError(paymentID, err)
+		}
+		return
+	}
+
+	w.mu.Lock()
+	from, ok := w.payments[paymentID]
+	if !ok {
+		w.mu.Unlock()
+		return // unwatched while polling
+	}
+	if info.IsPaymentComplete() || info.IsPaymentFailed() {
+		delete(w.payments, paymentID)
+	} else {
+		w.payments[paymentID] = info.Status
+	}
+	w.mu.Unlock()
+
+	if from == info.Status {
+		return
+	}
+	w.emit(ctx, PaymentTransition{From: from, To: info.Status, Info: info})
+}
+
+func (w *PaymentWatcher) emit(ctx context.Context, t PaymentTransition) {
+	if w.options.onTransition != nil {
+		w.options.onTransition(t)
+		return
+	}
+	select {
+	case w.events <- t:
+	case <-ctx.Done():
+	}
+}
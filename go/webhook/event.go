@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	setto "github.com/setto-labs/setto-server-sdk/go"
+)
+
+// ErrInvalidPayload is returned for a webhook body or event data that cannot
+// be decoded. Handler responds with 400, since redelivering the same payload
+// cannot succeed.
+var ErrInvalidPayload = errors.New("setto: invalid webhook payload")
+
+// EventType identifies the kind of a webhook event.
+type EventType string
+
+// Payment events, one per PaymentStatus.
+const (
+	EventPaymentPending   EventType = "payment.pending"
+	EventPaymentSubmitted EventType = "payment.submitted"
+	EventPaymentIncluded  EventType = "payment.included"
+	EventPaymentFailed    EventType = "payment.failed"
+	EventPaymentCancelled EventType = "payment.cancelled"
+)
+
+// Account events.
+const (
+	EventAccountLinked   EventType = "account.linked"
+	EventAccountUnlinked EventType = "account.unlinked"
+)
+
+// IsPayment reports whether t is a payment event.
+func (t EventType) IsPayment() bool {
+	return strings.HasPrefix(string(t), "payment.")
+}
+
+// IsAccount reports whether t is an account event.
+func (t EventType) IsAccount() bool {
+	return strings.HasPrefix(string(t), "account.")
+}
+
+// Event is the envelope shared by all webhook deliveries.
+//
+// Field names are camelCase, like the PaymentInfo embedded in payment events,
+// which uses the format of GET /api/external/payment/{id}; one payload thus
+// follows one convention.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      EventType       `json:"type"`
+	CreatedAt int64           `json:"createdAt"` // Unix ms
+	Data      json.RawMessage `json:"data"`
+}
+
+// PaymentEvent is delivered when a payment changes status.
+type PaymentEvent struct {
+	Event
+	Payment        setto.PaymentInfo
+	PreviousStatus setto.PaymentStatus // Empty if unknown
+}
+
+// AccountEvent is delivered when a Setto account is linked to or unlinked
+// from the integration.
+type AccountEvent struct {
+	Event
+	UserID          string
+	IsPhoneVerified bool
+}
+
+type paymentEventData struct {
+	Payment        setto.PaymentInfo   `json:"payment"`
+	PreviousStatus setto.PaymentStatus `json:"previousStatus"`
+}
+
+type accountEventData struct {
+	UserID          string `json:"userId"`
+	IsPhoneVerified bool   `json:"isPhoneVerified"`
+}
+
+// ParseEvent decodes the envelope of a webhook body.
+// It does not verify the signature; use VerifySignature or Handler for that.
+func ParseEvent(body []byte) (*Event, error) {
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	if e.ID == "" || e.Type == "" {
+		return nil, fmt.Errorf("%w: missing id or type", ErrInvalidPayload)
+	}
+	return &e, nil
+}
+
+// PaymentEvent decodes the data of a payment event.
+func (e *Event) PaymentEvent() (*PaymentEvent, error) {
+	var data paymentEventData
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("%w: %s event data: %w", ErrInvalidPayload, e.Type, err)
+	}
+	return &PaymentEvent{
+		Event:          *e,
+		Payment:        data.Payment,
+		PreviousStatus: data.PreviousStatus,
+	}, nil
+}
+
+// AccountEvent decodes the data of an account event.
+func (e *Event) AccountEvent() (*AccountEvent, error) {
+	var data accountEventData
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("%w: %s event data: %w", ErrInvalidPayload, e.Type, err)
+	}
+	return &AccountEvent{
+		Event:           *e,
+		UserID:          data.UserID,
+		IsPhoneVerified: data.IsPhoneVerified,
+	}, nil
+}
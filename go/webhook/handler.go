@@ -0,0 +1,269 @@
+// Package webhook receives Setto webhook deliveries.
+//
+// Handler is an http.Handler that verifies the Setto-Signature header,
+// decodes the event and dispatches it to registered handlers.
+//
+// The wire protocol implemented here (the Setto-Signature header and its
+// HMAC scheme, the event type names and the JSON envelope) is provisional:
+// it is not yet part of Setto's published API documentation and may change
+// before webhooks are generally available.
+//
+//	h := webhook.NewHandler([]string{os.Getenv("SETTO_WEBHOOK_SECRET")})
+//	h.OnPayment(func(ctx context.Context, e *webhook.PaymentEvent) error {
+//	    if e.Payment.IsPaymentComplete() {
+//	        return fulfill(ctx, e.Payment.PaymentID)
+//	    }
+//	    return nil
+//	})
+//	http.Handle("/webhooks/setto", h)
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultMaxBodySize = 1 << 20 // 1 MiB
+
+// PaymentHandler handles a payment event. Returning an error makes the
+// delivery fail with HTTP 500 so that Setto retries it.
+type PaymentHandler func(ctx context.Context, e *PaymentEvent) error
+
+// AccountHandler handles an account event. Returning an error makes the
+// delivery fail with HTTP 500 so that Setto retries it.
+type AccountHandler func(ctx context.Context, e *AccountEvent) error
+
+// Option configures a Handler.
+type Option func(*options)
+
+type options struct {
+	tolerance   time.Duration
+	maxBodySize int64
+	onError     func(r *http.Request, err error)
+	store       EventStore
+}
+
+// WithTolerance sets the maximum age of a signature timestamp. Default: 5m.
+func WithTolerance(d time.Duration) Option {
+	return func(o *options) { o.tolerance = d }
+}
+
+// WithMaxBodySize limits the size of accepted request bodies. Default: 1 MiB.
+func WithMaxBodySize(n int64) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxBodySize = n
+		}
+	}
+}
+
+// WithErrorHandler registers fn to be called for every rejected or failed
+// delivery, e.g. for logging. Signature failures are reported with the
+// package's Err* sentinel errors.
+func WithErrorHandler(fn func(r *http.Request, err error)) Option {
+	return func(o *options) { o.onError = fn }
+}
+
+// WithEventStore deduplicates deliveries by event ID and records their
+// processing outcome in store. An event that was processed successfully is
+// acknowledged without running handlers again; a failed one is retried on
+// redelivery. Default: no store, every delivery is dispatched.
+func WithEventStore(store EventStore) Option {
+	return func(o *options) { o.store = store }
+}
+
+// Handler is an http.Handler for Setto webhook deliveries.
+type Handler struct {
+	secrets []string
+	options *options
+	now     func() time.Time
+
+	mu      sync.RWMutex
+	payment []PaymentHandler
+	account []AccountHandler
+}
+
+// NewHandler creates a webhook handler. Deliveries are accepted if signed
+// with any of secrets, so a new secret can be added before the old one is
+// retired.
+func NewHandler(secrets []string, opts ...Option) *Handler {
+	options := &options{
+		tolerance:   DefaultTolerance,
+		maxBodySize: defaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &Handler{
+		secrets: secrets,
+		options: options,
+		now:     time.Now,
+	}
+}
+
+// OnPayment registers fn for all payment events.
+func (h *Handler) OnPayment(fn PaymentHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.payment = append(h.payment, fn)
+}
+
+// OnAccount registers fn for all account events.
+func (h *Handler) OnAccount(fn AccountHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.account = append(h.account, fn)
+}
+
+// ServeHTTP verifies and dispatches a delivery. It responds with 400 for
+// malformed requests and event data that cannot be decoded, 401 for
+// signature failures, 409 if the event is being processed by another
+// delivery, 500 if a registered handler or the event store fails, and 200
+// otherwise. Unknown event types are acknowledged and ignored.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, http.StatusMethodNotAllowed, fmt.Errorf("setto: webhook method %s not allowed", r.Method))
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.options.maxBodySize+1))
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, fmt.Errorf("setto: read webhook body: %w", err))
+		return
+	}
+	if int64(len(body)) > h.options.maxBodySize {
+		h.fail(w, r, http.StatusRequestEntityTooLarge, fmt.Errorf("setto: webhook body exceeds %d bytes", h.options.maxBodySize))
+		return
+	}
+
+	err = VerifySignature(r.Header.Get(SignatureHeader), body, h.secrets, h.options.tolerance, h.now())
+	switch {
+	case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignatureHeader):
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	case err != nil:
+		h.fail(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	event, err := ParseEvent(body)
+	if err != nil {
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	err = h.process(r.Context(), event)
+	switch {
+	case errors.Is(err, ErrEventInProgress):
+		h.fail(w, r, http.StatusConflict, err)
+		return
+	case errors.Is(err, ErrInvalidPayload):
+		h.fail(w, r, http.StatusBadRequest, err)
+		return
+	case err != nil:
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// process dispatches event, deduplicating it through the event store if one
+// is configured.
+func (h *Handler) process(ctx context.Context, event *Event) error {
+	store := h.options.store
+	if store == nil {
+		return h.Dispatch(ctx, event)
+	}
+
+	attempt, err := store.Begin(ctx, event)
+	if err != nil || attempt == 0 {
+		return err
+	}
+
+	procErr := h.Dispatch(ctx, event)
+	if err := store.Complete(context.WithoutCancel(ctx), event.ID, attempt, procErr); err != nil {
+		return errors.Join(procErr, err)
+	}
+	return procErr
+}
+
+// Replay dispatches the stored events of a payment again, oldest first,
+// including events that were already processed, and records their new
+// outcomes. Each event is claimed first, so an event that a live delivery is
+// processing is skipped with ErrEventInProgress rather than run twice.
+// It requires WithEventStore and returns the joined errors.
+func (h *Handler) Replay(ctx context.Context, paymentID string) error {
+	store := h.options.store
+	if store == nil {
+		return errors.New("setto: webhook replay requires an event store")
+	}
+
+	events, err := store.EventsForPayment(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, stored := range events {
+		event := stored.Event
+		attempt, err := store.Claim(ctx, event.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("setto: replay webhook event %s: %w", event.ID, err))
+			continue
+		}
+		procErr := h.Dispatch(ctx, &event)
+		if err := store.Complete(context.WithoutCancel(ctx), event.ID, attempt, procErr); err != nil {
+			errs = append(errs, err)
+		}
+		if procErr != nil {
+			errs = append(errs, procErr)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Dispatch decodes event and runs the handlers registered for its type.
+// It stops at the first handler error. Events of unknown types are ignored.
+func (h *Handler) Dispatch(ctx context.Context, event *Event) error {
+	h.mu.RLock()
+	payment, account := h.payment, h.account
+	h.mu.RUnlock()
+
+	switch {
+	case event.Type.IsPayment() && len(payment) > 0:
+		e, err := event.PaymentEvent()
+		if err != nil {
+			return err
+		}
+		for _, fn := range payment {
+			if err := fn(ctx, e); err != nil {
+				return fmt.Errorf("setto: %s handler for event %s: %w", event.Type, event.ID, err)
+			}
+		}
+	case event.Type.IsAccount() && len(account) > 0:
+		e, err := event.AccountEvent()
+		if err != nil {
+			return err
+		}
+		for _, fn := range account {
+			if err := fn(ctx, e); err != nil {
+				return fmt.Errorf("setto: %s handler for event %s: %w", event.Type, event.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.options.onError != nil {
+		h.options.onError(r, err)
+	}
+	http.Error(w, http.StatusText(status), status)
+}
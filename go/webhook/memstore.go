@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is the minimum time between two sweeps of expired
+// MemoryStore records.
+const memorySweepInterval = time.Minute
+
+// MemoryStore is an in-process EventStore. Records are lost on restart, so
+// it suits single-instance deployments and tests. Records are evicted once
+// they have not been updated for the retention period (see WithRetention).
+type MemoryStore struct {
+	options *storeOptions
+	now     func() time.Time
+
+	mu        sync.Mutex
+	events    map[string]*StoredEvent
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore(opts ...StoreOption) *MemoryStore {
+	return &MemoryStore{
+		options: newStoreOptions(opts),
+		now:     time.Now,
+		events:  make(map[string]*StoredEvent),
+	}
+}
+
+// Begin implements EventStore.
+func (s *MemoryStore) Begin(_ context.Context, event *Event) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	stored, ok := s.events[event.ID]
+	if !ok {
+		s.events[event.ID] = &StoredEvent{
+			Event:     *event,
+			PaymentID: paymentIDOf(event),
+			Status:    EventStatusProcessing,
+			Attempts:  1,
+			UpdatedAt: now,
+		}
+		return 1, nil
+	}
+
+	switch stored.Status {
+	case EventStatusSucceeded:
+		return 0, nil
+	case EventStatusProcessing:
+		if now.Sub(stored.UpdatedAt) < s.options.processingTimeout {
+			return 0, ErrEventInProgress
+		}
+	}
+	stored.Status = EventStatusProcessing
+	stored.Attempts++
+	stored.UpdatedAt = now
+	return stored.Attempts, nil
+}
+
+// Claim implements EventStore.
+func (s *MemoryStore) Claim(_ context.Context, eventID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	stored, ok := s.events[eventID]
+	if !ok {
+		return 0, fmt.Errorf("setto: webhook event %s not found", eventID)
+	}
+	if stored.Status == EventStatusProcessing && now.Sub(stored.UpdatedAt) < s.options.processingTimeout {
+		return 0, ErrEventInProgress
+	}
+	stored.Status = EventStatusProcessing
+	stored.Attempts++
+	stored.UpdatedAt = now
+	return stored.Attempts, nil
+}
+
+// Complete implements EventStore.
+func (s *MemoryStore) Complete(_ context.Context, eventID string, attempt int, procErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.events[eventID]
+	if !ok {
+		return fmt.Errorf("setto: webhook event %s not found", eventID)
+	}
+	if stored.Attempts != attempt || stored.Status != EventStatusProcessing {
+		return ErrClaimLost
+	}
+	stored.Status = EventStatusSucceeded
+	if procErr != nil {
+		stored.Status = EventStatusFailed
+	}
+	stored.LastError = errorString(procErr)
+	stored.UpdatedAt = s.now()
+	return nil
+}
+
+// EventsForPayment implements EventStore.
+func (s *MemoryStore) EventsForPayment(_ context.Context, paymentID string) ([]*StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []*StoredEvent
+	for _, stored := range s.events {
+		if stored.PaymentID == paymentID {
+			copied := *stored
+			events = append(events, &copied)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].CreatedAt != events[j].CreatedAt {
+			return events[i].CreatedAt < events[j].CreatedAt
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// sweep evicts records that have not been updated for the retention period.
+// It runs at most once per memorySweepInterval. s.mu must be held.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for id, stored := range s.events {
+		if now.Sub(stored.UpdatedAt) >= s.options.retention {
+			delete(s.events, id)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the HTTP header carrying the webhook signature.
+// Its value has the form "t=<unix seconds>,v1=<hex HMAC-SHA256>", where the
+// HMAC is computed over "<t>.<body>". Several v1 entries may be present
+// while Setto rotates its signing secret. Like the rest of the wire
+// protocol, this scheme is provisional; see the package documentation.
+const SignatureHeader = "Setto-Signature"
+
+// DefaultTolerance is the maximum allowed age of a signature timestamp.
+const DefaultTolerance = 5 * time.Minute
+
+// Signature verification errors.
+var (
+	ErrMissingSignature        = errors.New("setto: webhook signature header is missing")
+	ErrInvalidSignatureHeader  = errors.New("setto: webhook signature header is malformed")
+	ErrTimestampOutOfTolerance = errors.New("setto: webhook timestamp is outside the tolerance window")
+	ErrSignatureMismatch       = errors.New("setto: webhook signature does not match")
+	ErrNoSecrets               = errors.New("setto: no webhook secrets configured")
+)
+
+// Sign returns a signature header value for body, signed with secret at t.
+// It is useful for testing webhook receivers.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + computeMAC(secret, ts, body)
+}
+
+// VerifySignature checks header against body using any of secrets.
+// The timestamp must be within tolerance of now; a tolerance <= 0 disables
+// the check.
+func VerifySignature(header string, body []byte, secrets []string, tolerance time.Duration, now time.Time) error {
+	if len(secrets) == 0 {
+		return ErrNoSecrets
+	}
+	if header == "" {
+		return ErrMissingSignature
+	}
+
+	ts, sigs, err := parseSignatureHeader(header)
+	if err != nil {
+		return err
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", ErrInvalidSignatureHeader)
+	}
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: signed %s ago", ErrTimestampOutOfTolerance, age.Round(time.Second))
+		}
+	}
+
+	for _, secret := range secrets {
+		expected := []byte(computeMAC(secret, ts, body))
+		for _, sig := range sigs {
+			if hmac.Equal(expected, []byte(sig)) {
+				return nil
+			}
+		}
+	}
+	return ErrSignatureMismatch
+}
+
+// parseSignatureHeader splits a header into its timestamp and v1 signatures.
+func parseSignatureHeader(header string) (ts string, sigs []string, err error) {
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return "", nil, ErrInvalidSignatureHeader
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sigs = append(sigs, strings.ToLower(value))
+		}
+	}
+	if ts == "" {
+		return "", nil, fmt.Errorf("%w: no timestamp", ErrInvalidSignatureHeader)
+	}
+	if len(sigs) == 0 {
+		return "", nil, fmt.Errorf("%w: no v1 signature", ErrInvalidSignatureHeader)
+	}
+	return ts, sigs, nil
+}
+
+func computeMAC(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
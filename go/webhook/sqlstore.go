@@ -0,0 +1,277 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTableName = "setto_webhook_events"
+
+// Dialect is the SQL dialect of the database behind an SQLStore. It selects
+// the bind parameter style and the schema syntax.
+type Dialect int
+
+const (
+	DialectSQLite   Dialect = iota // ? parameters
+	DialectMySQL                   // ? parameters, index declared in CREATE TABLE
+	DialectPostgres                // $1 parameters
+)
+
+// WithTableName sets the table used by SQLStore. Default: "setto_webhook_events".
+func WithTableName(name string) StoreOption {
+	return func(o *storeOptions) {
+		if name != "" {
+			o.table = name
+		}
+	}
+}
+
+// WithDialect sets the SQL dialect used by SQLStore. Default: DialectSQLite.
+func WithDialect(d Dialect) StoreOption {
+	return func(o *storeOptions) { o.dialect = d }
+}
+
+// SQLStore is an EventStore backed by database/sql, shared by every instance
+// of a service. Create its table with CreateTable or from Schema.
+type SQLStore struct {
+	db      *sql.DB
+	options *storeOptions
+	now     func() time.Time
+}
+
+// NewSQLStore creates an SQLStore using db.
+func NewSQLStore(db *sql.DB, opts ...StoreOption) *SQLStore {
+	return &SQLStore{
+		db:      db,
+		options: newStoreOptions(opts),
+		now:     time.Now,
+	}
+}
+
+// Schema returns the CREATE TABLE statements for the store's table.
+// Timestamps are stored as Unix milliseconds. MySQL has no CREATE INDEX IF
+// NOT EXISTS, so for DialectMySQL the index is declared in the table itself,
+// and its payload columns are MEDIUMTEXT because TEXT holds only 64 KiB,
+// less than the bodies Handler accepts.
+func (s *SQLStore) Schema() []string {
+	t := s.options.table
+	if s.options.dialect == DialectMySQL {
+		return []string{
+			`CREATE TABLE IF NOT EXISTS ` + t + ` (
+	event_id   VARCHAR(255) NOT NULL PRIMARY KEY,
+	event_type VARCHAR(64)  NOT NULL,
+	payment_id VARCHAR(255) NOT NULL,
+	created_at BIGINT       NOT NULL,
+	data       MEDIUMTEXT   NOT NULL,
+	status     VARCHAR(16)  NOT NULL,
+	attempts   INTEGER      NOT NULL,
+	last_error MEDIUMTEXT   NOT NULL,
+	updated_at BIGINT       NOT NULL,
+	INDEX ` + t + `_payment_id_idx (payment_id)
+)`,
+		}
+	}
+	return []string{
+		`CREATE TABLE IF NOT EXISTS ` + t + ` (
+	event_id   VARCHAR(255) NOT NULL PRIMARY KEY,
+	event_type VARCHAR(64)  NOT NULL,
+	payment_id VARCHAR(255) NOT NULL,
+	created_at BIGINT       NOT NULL,
+	data       TEXT         NOT NULL,
+	status     VARCHAR(16)  NOT NULL,
+	attempts   INTEGER      NOT NULL,
+	last_error TEXT         NOT NULL,
+	updated_at BIGINT       NOT NULL
+)`,
+		`CREATE INDEX IF NOT EXISTS ` + t + `_payment_id_idx ON ` + t + ` (payment_id)`,
+	}
+}
+
+// CreateTable executes Schema.
+func (s *SQLStore) CreateTable(ctx context.Context) error {
+	for _, stmt := range s.Schema() {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("setto: create webhook event table: %w", err)
+		}
+	}
+	return nil
+}
+
+// Begin implements EventStore.
+func (s *SQLStore) Begin(ctx context.Context, event *Event) (int, error) {
+	now := s.now().UnixMilli()
+
+	_, insertErr := s.db.ExecContext(ctx, s.query(`INSERT INTO `+s.options.table+
+		` (event_id, event_type, payment_id, created_at, data, status, attempts, last_error, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, 1, '', ?)`),
+		event.ID, string(event.Type), paymentIDOf(event), event.CreatedAt, string(event.Data),
+		string(EventStatusProcessing), now)
+	if insertErr == nil {
+		return 1, nil
+	}
+
+	// The insert failed, most likely because the event exists. Take it over
+	// if its previous attempt failed or was abandoned.
+	status, attempts, updatedAt, err := s.load(ctx, event.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, fmt.Errorf("setto: begin webhook event %s: %w", event.ID, insertErr)
+	case err != nil:
+		return 0, fmt.Errorf("setto: begin webhook event %s: %w", event.ID, err)
+	case status == EventStatusSucceeded:
+		return 0, nil
+	case status == EventStatusProcessing && !s.stale(updatedAt, now):
+		return 0, ErrEventInProgress
+	}
+	attempt, err := s.advance(ctx, event.ID, attempts, now)
+	if err != nil {
+		return 0, fmt.Errorf("setto: begin webhook event %s: %w", event.ID, err)
+	}
+	if attempt == 0 {
+		return 0, ErrEventInProgress
+	}
+	return attempt, nil
+}
+
+// Claim implements EventStore.
+func (s *SQLStore) Claim(ctx context.Context, eventID string) (int, error) {
+	now := s.now().UnixMilli()
+
+	status, attempts, updatedAt, err := s.load(ctx, eventID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, fmt.Errorf("setto: webhook event %s not found", eventID)
+	case err != nil:
+		return 0, fmt.Errorf("setto: claim webhook event %s: %w", eventID, err)
+	case status == EventStatusProcessing && !s.stale(updatedAt, now):
+		return 0, ErrEventInProgress
+	}
+	attempt, err := s.advance(ctx, eventID, attempts, now)
+	if err != nil {
+		return 0, fmt.Errorf("setto: claim webhook event %s: %w", eventID, err)
+	}
+	if attempt == 0 {
+		return 0, ErrEventInProgress
+	}
+	return attempt, nil
+}
+
+// Complete implements EventStore. The update only applies while attempt
+// still holds the event.
+func (s *SQLStore) Complete(ctx context.Context, eventID string, attempt int, procErr error) error {
+	status := EventStatusSucceeded
+	if procErr != nil {
+		status = EventStatusFailed
+	}
+
+	res, err := s.db.ExecContext(ctx, s.query(`UPDATE `+s.options.table+
+		` SET status = ?, last_error = ?, updated_at = ?
+		WHERE event_id = ? AND attempts = ? AND status = ?`),
+		string(status), errorString(procErr), s.now().UnixMilli(),
+		eventID, attempt, string(EventStatusProcessing))
+	if err != nil {
+		return fmt.Errorf("setto: complete webhook event %s: %w", eventID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 1 {
+		return nil
+	}
+
+	_, _, _, err = s.load(ctx, eventID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("setto: webhook event %s not found", eventID)
+	case err != nil:
+		return fmt.Errorf("setto: complete webhook event %s: %w", eventID, err)
+	}
+	return ErrClaimLost
+}
+
+// load reads the processing record of an event.
+func (s *SQLStore) load(ctx context.Context, eventID string) (status EventStatus, attempts int, updatedAt int64, err error) {
+	var st string
+	err = s.db.QueryRowContext(ctx, s.query(`SELECT status, attempts, updated_at FROM `+s.options.table+
+		` WHERE event_id = ?`), eventID).Scan(&st, &attempts, &updatedAt)
+	return EventStatus(st), attempts, updatedAt, err
+}
+
+// advance moves an event to processing under the next attempt number, unless
+// another attempt advanced it since its record was loaded with attempts. It
+// returns the new attempt number, or 0 if another attempt won.
+func (s *SQLStore) advance(ctx context.Context, eventID string, attempts int, now int64) (int, error) {
+	res, err := s.db.ExecContext(ctx, s.query(`UPDATE `+s.options.table+
+		` SET status = ?, attempts = ?, updated_at = ?
+		WHERE event_id = ? AND attempts = ?`),
+		string(EventStatusProcessing), attempts+1, now,
+		eventID, attempts)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 1 {
+		return attempts + 1, nil
+	}
+	return 0, nil
+}
+
+// stale reports whether a processing record last updated at updatedAt has
+// exceeded the processing timeout.
+func (s *SQLStore) stale(updatedAt, now int64) bool {
+	return updatedAt < now-s.options.processingTimeout.Milliseconds()
+}
+
+// EventsForPayment implements EventStore.
+func (s *SQLStore) EventsForPayment(ctx context.Context, paymentID string) ([]*StoredEvent, error) {
+	rows, err := s.db.QueryContext(ctx, s.query(`SELECT event_id, event_type, payment_id, created_at, data,
+		status, attempts, last_error, updated_at FROM `+s.options.table+`
+		WHERE payment_id = ? ORDER BY created_at, event_id`), paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("setto: query webhook events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*StoredEvent
+	for rows.Next() {
+		var (
+			e         StoredEvent
+			eventType string
+			data      string
+			status    string
+			updatedAt int64
+		)
+		if err := rows.Scan(&e.ID, &eventType, &e.PaymentID, &e.CreatedAt, &data,
+			&status, &e.Attempts, &e.LastError, &updatedAt); err != nil {
+			return nil, fmt.Errorf("setto: scan webhook event: %w", err)
+		}
+		e.Type = EventType(eventType)
+		e.Data = []byte(data)
+		e.Status = EventStatus(status)
+		e.UpdatedAt = time.UnixMilli(updatedAt)
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("setto: query webhook events: %w", err)
+	}
+	return events, nil
+}
+
+// query rewrites ? placeholders for the configured driver style.
+func (s *SQLStore) query(q string) string {
+	if s.options.dialect != DialectPostgres {
+		return q
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DefaultProcessingTimeout is how long an event may stay in processing before
+// a redelivery is allowed to take it over, e.g. after a crash.
+const DefaultProcessingTimeout = 5 * time.Minute
+
+// DefaultRetention is how long MemoryStore keeps the record of an event after
+// it was last updated.
+const DefaultRetention = 72 * time.Hour
+
+// ErrEventInProgress is returned by EventStore.Begin and EventStore.Claim when
+// another delivery of the same event is still being processed. Handler
+// responds with 409 so that Setto retries later.
+var ErrEventInProgress = errors.New("setto: webhook event is already being processed")
+
+// ErrClaimLost is returned by EventStore.Complete when the attempt no longer
+// holds the event, because a redelivery took it over after the processing
+// timeout. The outcome of the stale attempt is not recorded.
+var ErrClaimLost = errors.New("setto: webhook event was taken over by another attempt")
+
+// EventStatus is the processing state of a stored event.
+type EventStatus string
+
+const (
+	EventStatusProcessing EventStatus = "processing"
+	EventStatusSucceeded  EventStatus = "succeeded"
+	EventStatusFailed     EventStatus = "failed"
+)
+
+// StoredEvent is an event together with its processing record.
+type StoredEvent struct {
+	Event
+	PaymentID string // Empty for non-payment events
+	Status    EventStatus
+	Attempts  int
+	LastError string
+	UpdatedAt time.Time
+}
+
+// EventStore records webhook deliveries so that each event is processed at
+// least once but fulfilled only once. Implementations must be safe for
+// concurrent use.
+type EventStore interface {
+	// Begin records the start of processing event. It returns the attempt
+	// number that holds the event if it should be processed, 0 if it already
+	// succeeded, and ErrEventInProgress if another delivery is processing it.
+	Begin(ctx context.Context, event *Event) (attempt int, err error)
+
+	// Claim marks a stored event as processing again, whatever its previous
+	// outcome, so that it can be replayed, and returns the attempt number
+	// that holds it. It returns ErrEventInProgress if another delivery is
+	// processing it.
+	Claim(ctx context.Context, eventID string) (attempt int, err error)
+
+	// Complete records the outcome of the given attempt. A non-nil procErr
+	// marks the event as failed, so a redelivery is processed again. It
+	// returns ErrClaimLost if attempt no longer holds the event.
+	Complete(ctx context.Context, eventID string, attempt int, procErr error) error
+
+	// EventsForPayment returns the stored events of a payment, oldest first.
+	EventsForPayment(ctx context.Context, paymentID string) ([]*StoredEvent, error)
+}
+
+// StoreOption configures an EventStore created by this package.
+type StoreOption func(*storeOptions)
+
+type storeOptions struct {
+	processingTimeout time.Duration
+	retention         time.Duration
+	table             string
+	dialect           Dialect
+}
+
+func newStoreOptions(opts []StoreOption) *storeOptions {
+	o := &storeOptions{
+		processingTimeout: DefaultProcessingTimeout,
+		retention:         DefaultRetention,
+		table:             defaultTableName,
+		dialect:           DialectSQLite,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithProcessingTimeout sets how long an event may stay in processing before
+// a redelivery takes it over. Default: DefaultProcessingTimeout.
+func WithProcessingTimeout(d time.Duration) StoreOption {
+	return func(o *storeOptions) {
+		if d > 0 {
+			o.processingTimeout = d
+		}
+	}
+}
+
+// WithRetention sets how long MemoryStore keeps an event after its last
+// update. A redelivery that arrives later is processed again. SQLStore keeps
+// rows until they are deleted; prune its table from a scheduled job.
+// Default: DefaultRetention.
+func WithRetention(d time.Duration) StoreOption {
+	return func(o *storeOptions) {
+		if d > 0 {
+			o.retention = d
+		}
+	}
+}
+
+// paymentIDOf returns the payment ID of a payment event, or "" otherwise.
+func paymentIDOf(e *Event) string {
+	if !e.Type.IsPayment() {
+		return ""
+	}
+	var data paymentEventData
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return ""
+	}
+	return data.Payment.PaymentID
+}
+
+// errorString returns err's message, or "" for nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}